@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &AccessProfileDataSource{}
@@ -105,7 +106,7 @@ func (d *AccessProfileDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	tflog.Info(ctx, "Reading Access Profile data source", map[string]interface{}{"name": data.Name.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.AccessProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -113,7 +114,7 @@ func (d *AccessProfileDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	accessProfiles, err := client.GetAccessProfileByName(ctx, data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Access Profile with name %s not found", data.Name.ValueString()))
 			return
 		}
@@ -116,7 +116,7 @@ func (d *DimensionDataSource) Read(ctx context.Context, req datasource.ReadReque
 		"role_id": data.RoleID.ValueString(),
 	})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &GovernanceGroupDataSource{}
@@ -83,7 +84,7 @@ func (d *GovernanceGroupDataSource) Read(ctx context.Context, req datasource.Rea
 
 	tflog.Info(ctx, "Reading Governance Group data source", map[string]interface{}{"name": data.Name.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -91,7 +92,7 @@ func (d *GovernanceGroupDataSource) Read(ctx context.Context, req datasource.Rea
 
 	governanceGroups, err := client.GetGovernanceGroupByName(ctx, data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Governance Group with name %s not found", data.Name.ValueString()))
 			return
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &IdentityDataSource{}
@@ -135,19 +136,19 @@ func (d *IdentityDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Identities(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
 	}
 
-	var identity *Identity
+	var identity *identitynow.Identity
 
 	if !data.Alias.IsNull() && alias != "" {
 		tflog.Info(ctx, "Reading Identity data source by alias", map[string]interface{}{"alias": alias})
 		identities, err := client.GetIdentityByAlias(ctx, alias)
 		if err != nil {
-			if _, notFound := err.(*NotFoundError); notFound {
+			if identitynow.IsNotFound(err) {
 				resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Identity with alias %s not found", alias))
 				return
 			}
@@ -161,7 +162,7 @@ func (d *IdentityDataSource) Read(ctx context.Context, req datasource.ReadReques
 		tflog.Info(ctx, "Reading Identity data source by email", map[string]interface{}{"email": email})
 		identities, err := client.GetIdentityByEmail(ctx, email)
 		if err != nil {
-			if _, notFound := err.(*NotFoundError); notFound {
+			if identitynow.IsNotFound(err) {
 				resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Identity with email %s not found", email))
 				return
 			}
@@ -105,7 +105,7 @@ func (d *RoleDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 
 	tflog.Info(ctx, "Reading Role data source", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
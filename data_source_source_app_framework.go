@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &SourceAppDataSource{}
@@ -93,7 +94,7 @@ func (d *SourceAppDataSource) Read(ctx context.Context, req datasource.ReadReque
 
 	tflog.Info(ctx, "Reading Source App data source", map[string]interface{}{"name": data.Name.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -101,7 +102,7 @@ func (d *SourceAppDataSource) Read(ctx context.Context, req datasource.ReadReque
 
 	sourceApps, err := client.GetSourceAppByName(ctx, data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Source App with name %s not found", data.Name.ValueString()))
 			return
 		}
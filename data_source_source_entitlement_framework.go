@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &SourceEntitlementDataSource{}
@@ -138,7 +139,7 @@ func (d *SourceEntitlementDataSource) Read(ctx context.Context, req datasource.R
 		"name":      data.Name.ValueString(),
 	})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Entitlements(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -146,7 +147,7 @@ func (d *SourceEntitlementDataSource) Read(ctx context.Context, req datasource.R
 
 	entitlements, err := client.GetSourceEntitlement(ctx, data.SourceID.ValueString(), data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Entitlement with name %s not found in source %s", data.Name.ValueString(), data.SourceID.ValueString()))
 			return
 		}
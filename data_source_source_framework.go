@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &SourceDataSource{}
@@ -110,7 +111,7 @@ func (d *SourceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	tflog.Info(ctx, "Reading Source data source", map[string]interface{}{"name": data.Name.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -118,7 +119,7 @@ func (d *SourceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	sources, err := client.GetSourceByName(ctx, data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Source with name %s not found", data.Name.ValueString()))
 			return
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ datasource.DataSource = &WorkflowDataSource{}
@@ -138,7 +139,7 @@ func (d *WorkflowDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	tflog.Info(ctx, "Reading Workflow data source", map[string]interface{}{"name": data.Name.ValueString()})
 
-	client, err := d.client.IdentityNowClient(ctx)
+	client, err := d.client.Workflows(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -146,7 +147,7 @@ func (d *WorkflowDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	workflow, err := client.GetWorkflowByName(ctx, data.Name.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.Diagnostics.AddError("Not Found", fmt.Sprintf("Workflow with name %s not found", data.Name.ValueString()))
 			return
 		}
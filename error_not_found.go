@@ -1,7 +0,0 @@
-package main
-
-type NotFoundError struct {
-	message string
-}
-
-func (e *NotFoundError) Error() string { return e.message }
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// exportedObject is an object read from the tenant with the state its resource would import
+type exportedObject struct {
+	typeName string
+	label    string
+	id       string
+	state    tftypes.Value
+}
+
+// runExport implements `terraform-provider-identitynow export`. It lists the objects of a tenant and writes
+// one .tf file per resource type plus imports.tf with the matching import blocks.
+// The provider is configured from the same IDENTITYNOW_* environment variables Terraform would use.
+func runExport(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	typesFlag := flags.String("types", "", "comma separated resource types to export, e.g. role,access_profile (default all)")
+	output := flags.String("output", ".", "directory the .tf files are written to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	selected, err := selectExportTypes(*typesFlag)
+	if err != nil {
+		return err
+	}
+
+	config, err := configureExportProvider(ctx)
+	if err != nil {
+		return err
+	}
+	// Exporting must never change the tenant
+	config.ReadOnly = true
+
+	services, err := config.apiServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	var objects []*exportedObject
+	schemas := map[string]schema.Schema{}
+	labels := map[string]map[string]bool{}
+	for _, t := range selected {
+		typeName := "identitynow_" + t.name
+		res := t.newResource()
+		res.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: config}, &resource.ConfigureResponse{})
+
+		var schemaResp resource.SchemaResponse
+		res.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+		schemas[typeName] = schemaResp.Schema
+		labels[typeName] = map[string]bool{}
+
+		listed, err := t.list(ctx, services, "")
+		if err != nil {
+			return fmt.Errorf("listing %s: %w", typeName, err)
+		}
+		for _, l := range listed {
+			state, diags := readResourceByID(ctx, res, l.id)
+			if err := diagnosticsError(diags); err != nil {
+				return fmt.Errorf("reading %s %s: %w", typeName, l.id, err)
+			}
+			if state.IsNull() {
+				// Deleted since it was listed
+				continue
+			}
+			objects = append(objects, &exportedObject{
+				typeName: typeName,
+				label:    uniqueExportLabel(labels[typeName], exportStateName(state)),
+				id:       l.id,
+				state:    state,
+			})
+		}
+	}
+
+	// Identifiers of exported objects are written as references to their resources
+	references := map[string]hcl.Traversal{}
+	for _, object := range objects {
+		references[object.id] = hcl.Traversal{
+			hcl.TraverseRoot{Name: object.typeName},
+			hcl.TraverseAttr{Name: object.label},
+			hcl.TraverseAttr{Name: "id"},
+		}
+	}
+
+	files := map[string]*hclwrite.File{}
+	imports := hclwrite.NewEmptyFile()
+	for _, object := range objects {
+		file, ok := files[object.typeName]
+		if !ok {
+			file = hclwrite.NewEmptyFile()
+			files[object.typeName] = file
+		} else {
+			file.Body().AppendNewline()
+		}
+		block := file.Body().AppendNewBlock("resource", []string{object.typeName, object.label})
+		writeExportBody(block.Body(), object.state, schemas[object.typeName].Attributes, schemas[object.typeName].Blocks, references, true)
+
+		if len(imports.Body().Blocks()) > 0 {
+			imports.Body().AppendNewline()
+		}
+		importBody := imports.Body().AppendNewBlock("import", nil).Body()
+		importBody.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: object.typeName},
+			hcl.TraverseAttr{Name: object.label},
+		})
+		importBody.SetAttributeValue("id", cty.StringVal(object.id))
+	}
+
+	if err := os.MkdirAll(*output, 0o755); err != nil {
+		return err
+	}
+	for _, t := range selected {
+		file, ok := files["identitynow_"+t.name]
+		if !ok {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(*output, t.name+".tf"), file.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(*output, "imports.tf"), imports.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Exported %d objects to %s\n", len(objects), *output)
+	return nil
+}
+
+// selectExportTypes resolves the -types flag, an empty value selects every type
+func selectExportTypes(value string) ([]listableType, error) {
+	if strings.TrimSpace(value) == "" {
+		return listableTypes, nil
+	}
+
+	requested := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "identitynow_")
+		if name != "" {
+			requested[name] = true
+		}
+	}
+
+	var selected []listableType
+	for _, t := range listableTypes {
+		if requested[t.name] {
+			selected = append(selected, t)
+			delete(requested, t.name)
+		}
+	}
+	if len(requested) > 0 {
+		var unknown, known []string
+		for name := range requested {
+			unknown = append(unknown, name)
+		}
+		for _, t := range listableTypes {
+			known = append(known, t.name)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unsupported export types %s, supported types are %s", strings.Join(unknown, ", "), strings.Join(known, ", "))
+	}
+	return selected, nil
+}
+
+// configureExportProvider configures the provider with an empty configuration, so every setting comes from the environment
+func configureExportProvider(ctx context.Context) (*Config, error) {
+	p := New(version)()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: nullAttributesValue(schemaType)},
+	}, &resp)
+	if err := diagnosticsError(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+	return resp.ResourceData.(*Config), nil
+}
+
+// nullAttributesValue is an object of the given type with every attribute null
+func nullAttributesValue(objectType tftypes.Type) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.(tftypes.Object).AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// diagnosticsError joins error diagnostics into a single error
+func diagnosticsError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags.Errors() {
+		errs = append(errs, fmt.Errorf("%s: %s", d.Summary(), d.Detail()))
+	}
+	return errors.Join(errs...)
+}
+
+// exportStateName is the name attribute of a state, used to label its resource
+func exportStateName(state tftypes.Value) string {
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		return ""
+	}
+	var name string
+	if v, ok := attributes["name"]; ok && v.IsKnown() && !v.IsNull() {
+		_ = v.As(&name)
+	}
+	return name
+}
+
+var exportLabelInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// uniqueExportLabel turns a name into a resource label that is not used yet
+func uniqueExportLabel(used map[string]bool, name string) string {
+	label := strings.Trim(exportLabelInvalid.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" {
+		label = "object"
+	}
+	if label[0] >= '0' && label[0] <= '9' {
+		label = "_" + label
+	}
+
+	unique := label
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// writeExportBody writes the configurable attributes and nested blocks of an object value.
+// Computed-only attributes, the top level id and null values are left out.
+func writeExportBody(body *hclwrite.Body, value tftypes.Value, attributes map[string]schema.Attribute, blocks map[string]schema.Block, references map[string]hcl.Traversal, topLevel bool) {
+	var fields map[string]tftypes.Value
+	if err := value.As(&fields); err != nil {
+		return
+	}
+
+	for _, name := range sortedExportNames(attributes) {
+		attribute := attributes[name]
+		if topLevel && name == "id" {
+			continue
+		}
+		if !attribute.IsRequired() && !attribute.IsOptional() {
+			continue
+		}
+		field, ok := fields[name]
+		if !ok || !field.IsKnown() || field.IsNull() {
+			continue
+		}
+		body.SetAttributeRaw(name, exportValueTokens(field, references))
+	}
+
+	for _, name := range sortedExportNames(blocks) {
+		field, ok := fields[name]
+		if !ok || !field.IsKnown() || field.IsNull() {
+			continue
+		}
+		switch block := blocks[name].(type) {
+		case schema.ListNestedBlock:
+			writeExportBlocks(body, name, field, block.NestedObject, references)
+		case schema.SetNestedBlock:
+			writeExportBlocks(body, name, field, block.NestedObject, references)
+		case schema.SingleNestedBlock:
+			writeExportBody(body.AppendNewBlock(name, nil).Body(), field, block.Attributes, block.Blocks, references, false)
+		}
+	}
+}
+
+// writeExportBlocks writes one nested block per element of a list or set value
+func writeExportBlocks(body *hclwrite.Body, name string, value tftypes.Value, nested schema.NestedBlockObject, references map[string]hcl.Traversal) {
+	var elements []tftypes.Value
+	if err := value.As(&elements); err != nil {
+		return
+	}
+	for _, element := range elements {
+		writeExportBody(body.AppendNewBlock(name, nil).Body(), element, nested.Attributes, nested.Blocks, references, false)
+	}
+}
+
+// exportValueTokens renders an attribute value, strings matching an exported object become references to it
+func exportValueTokens(value tftypes.Value, references map[string]hcl.Traversal) hclwrite.Tokens {
+	switch {
+	case value.Type().Is(tftypes.String):
+		var s string
+		_ = value.As(&s)
+		if traversal, ok := references[s]; ok {
+			return hclwrite.TokensForTraversal(traversal)
+		}
+		return hclwrite.TokensForValue(cty.StringVal(s))
+	case value.Type().Is(tftypes.Number):
+		n := new(big.Float)
+		_ = value.As(&n)
+		return hclwrite.TokensForValue(cty.NumberVal(n))
+	case value.Type().Is(tftypes.Bool):
+		var b bool
+		_ = value.As(&b)
+		return hclwrite.TokensForValue(cty.BoolVal(b))
+	case value.Type().Is(tftypes.List{}), value.Type().Is(tftypes.Set{}), value.Type().Is(tftypes.Tuple{}):
+		var elements []tftypes.Value
+		_ = value.As(&elements)
+		tuple := make([]hclwrite.Tokens, 0, len(elements))
+		for _, element := range elements {
+			tuple = append(tuple, exportValueTokens(element, references))
+		}
+		return hclwrite.TokensForTuple(tuple)
+	case value.Type().Is(tftypes.Map{}), value.Type().Is(tftypes.Object{}):
+		var fields map[string]tftypes.Value
+		_ = value.As(&fields)
+		var object []hclwrite.ObjectAttrTokens
+		for _, name := range sortedExportNames(fields) {
+			if !fields[name].IsKnown() || fields[name].IsNull() {
+				continue
+			}
+			// Map keys are arbitrary strings, object attributes are identifiers
+			key := hclwrite.TokensForValue(cty.StringVal(name))
+			if value.Type().Is(tftypes.Object{}) {
+				key = hclwrite.TokensForIdentifier(name)
+			}
+			object = append(object, hclwrite.ObjectAttrTokens{
+				Name:  key,
+				Value: exportValueTokens(fields[name], references),
+			})
+		}
+		return hclwrite.TokensForObject(object)
+	}
+	return hclwrite.TokensForValue(cty.NullVal(cty.DynamicPseudoType))
+}
+
+// sortedExportNames orders names alphabetically with name first, the way configurations are usually written
+func sortedExportNames[T any](values map[string]T) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "name") != (names[j] == "name") {
+			return names[i] == "name"
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
+)
+
+var _ function.Function = &CriteriaFunction{}
+
+// criteriaMaxDepth is how deep the membership criteria blocks of roles and dimensions nest
+const criteriaMaxDepth = 3
+
+// criteriaComparisons maps the comparison operators of criteria expressions to API operations
+var criteriaComparisons = map[string]string{
+	"==":          "EQUALS",
+	"!=":          "NOT_EQUALS",
+	"contains":    "CONTAINS",
+	"starts_with": "STARTS_WITH",
+	"ends_with":   "ENDS_WITH",
+}
+
+func NewCriteriaFunction() function.Function {
+	return &CriteriaFunction{}
+}
+
+// CriteriaFunction parses a criteria expression into the membership criteria block of roles and dimensions
+type CriteriaFunction struct{}
+
+func (f *CriteriaFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "criteria"
+}
+
+func (f *CriteriaFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a criteria expression into membership criteria",
+		MarkdownDescription: "Parses an expression such as `attribute.department == \"IT\" && account[<source id>].memberOf contains \"X\"` " +
+			"into an object shaped like the `criteria` block of `identitynow_role` and `identitynow_dimension` membership. " +
+			"Comparisons use `==`, `!=`, `contains`, `starts_with` and `ends_with`, they are combined with `&&` and `||` " +
+			"and grouped with parentheses. `&&` binds tighter than `||`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "expression",
+				MarkdownDescription: "Criteria expression",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: criteriaObjectType().AttrTypes},
+	}
+}
+
+func (f *CriteriaFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var expression string
+	resp.Error = req.Arguments.Get(ctx, &expression)
+	if resp.Error != nil {
+		return
+	}
+
+	criteria, err := parseCriteria(expression)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	var diags diag.Diagnostics
+	state := criteriaAPIToState(ctx, criteria, &diags)
+	if diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, state.Elements()[0].(types.Object))
+}
+
+// parseCriteria parses a criteria expression into API membership criteria
+func parseCriteria(expression string) (*identitynow.RoleMembershipCriteria, error) {
+	tokens, err := criteriaTokens(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &criteriaParser{tokens: tokens}
+	criteria, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != criteriaEnd {
+		return nil, fmt.Errorf("unexpected %q at position %d", token.text, token.position)
+	}
+	if depth := criteriaDepth(criteria); depth > criteriaMaxDepth {
+		return nil, fmt.Errorf("criteria nest %d levels deep, membership supports at most %d", depth, criteriaMaxDepth)
+	}
+	return criteria, nil
+}
+
+// criteriaDepth counts the levels of criteria blocks needed for c
+func criteriaDepth(c *identitynow.RoleMembershipCriteria) int {
+	depth := 0
+	for _, child := range c.Children {
+		depth = max(depth, criteriaDepth(child))
+	}
+	return depth + 1
+}
+
+type criteriaTokenKind int
+
+const (
+	criteriaEnd criteriaTokenKind = iota
+	criteriaWord
+	criteriaString
+	criteriaSymbol
+)
+
+type criteriaToken struct {
+	kind     criteriaTokenKind
+	text     string
+	position int
+}
+
+// criteriaTokens splits an expression into words, quoted strings and symbols. Words keep bracketed source ids,
+// e.g. account[2c91808a].memberOf is one word.
+func criteriaTokens(expression string) ([]criteriaToken, error) {
+	var tokens []criteriaToken
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, criteriaToken{kind: criteriaSymbol, text: string(c), position: i})
+			i++
+		case strings.HasPrefix(expression[i:], "&&") || strings.HasPrefix(expression[i:], "||") ||
+			strings.HasPrefix(expression[i:], "==") || strings.HasPrefix(expression[i:], "!="):
+			tokens = append(tokens, criteriaToken{kind: criteriaSymbol, text: expression[i : i+2], position: i})
+			i += 2
+		case c == '"':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(expression) && expression[j] != '"'; j++ {
+				if expression[j] == '\\' && j+1 < len(expression) {
+					j++
+				}
+				value.WriteByte(expression[j])
+			}
+			if j >= len(expression) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, criteriaToken{kind: criteriaString, text: value.String(), position: i})
+			i = j + 1
+		case isCriteriaWordByte(c):
+			j := i
+			for j < len(expression) && (isCriteriaWordByte(expression[j]) || expression[j] == '[') {
+				if expression[j] == '[' {
+					end := strings.IndexByte(expression[j:], ']')
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated [ at position %d", j)
+					}
+					j += end
+				}
+				j++
+			}
+			tokens = append(tokens, criteriaToken{kind: criteriaWord, text: expression[i:j], position: i})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+		}
+	}
+	return append(tokens, criteriaToken{kind: criteriaEnd, text: "end of expression", position: len(expression)}), nil
+}
+
+func isCriteriaWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// criteriaParser is a recursive descent parser over criteria tokens
+type criteriaParser struct {
+	tokens []criteriaToken
+	next   int
+}
+
+func (p *criteriaParser) peek() criteriaToken {
+	return p.tokens[p.next]
+}
+
+func (p *criteriaParser) take() criteriaToken {
+	token := p.tokens[p.next]
+	if token.kind != criteriaEnd {
+		p.next++
+	}
+	return token
+}
+
+func (p *criteriaParser) parseOr() (*identitynow.RoleMembershipCriteria, error) {
+	return p.parseJoined("||", "OR", p.parseAnd)
+}
+
+func (p *criteriaParser) parseAnd() (*identitynow.RoleMembershipCriteria, error) {
+	return p.parseJoined("&&", "AND", p.parsePrimary)
+}
+
+// parseJoined parses operands separated by symbol into one criteria of the operation. Operands of the same
+// operation are flattened, so a && (b && c) needs a single level of children.
+func (p *criteriaParser) parseJoined(symbol string, operation string, operand func() (*identitynow.RoleMembershipCriteria, error)) (*identitynow.RoleMembershipCriteria, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	joined := &identitynow.RoleMembershipCriteria{Operation: operation}
+	add := func(c *identitynow.RoleMembershipCriteria) {
+		if c.Operation == operation {
+			joined.Children = append(joined.Children, c.Children...)
+		} else {
+			joined.Children = append(joined.Children, c)
+		}
+	}
+	add(first)
+	for p.peek().kind == criteriaSymbol && p.peek().text == symbol {
+		p.take()
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		add(next)
+	}
+	if len(joined.Children) == 1 && first.Operation != operation {
+		return first, nil
+	}
+	return joined, nil
+}
+
+func (p *criteriaParser) parsePrimary() (*identitynow.RoleMembershipCriteria, error) {
+	token := p.take()
+	if token.kind == criteriaSymbol && token.text == "(" {
+		criteria, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.take(); closing.kind != criteriaSymbol || closing.text != ")" {
+			return nil, fmt.Errorf("expected ) at position %d, got %q", closing.position, closing.text)
+		}
+		return criteria, nil
+	}
+	if token.kind != criteriaWord {
+		return nil, fmt.Errorf("expected an attribute at position %d, got %q", token.position, token.text)
+	}
+	key, err := criteriaKey(token)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := p.take()
+	operation, ok := criteriaComparisons[comparison.text]
+	if !ok || comparison.kind == criteriaString {
+		return nil, fmt.Errorf("expected ==, !=, contains, starts_with or ends_with at position %d, got %q", comparison.position, comparison.text)
+	}
+	value := p.take()
+	if value.kind != criteriaString {
+		return nil, fmt.Errorf("expected a quoted value at position %d, got %q", value.position, value.text)
+	}
+	return &identitynow.RoleMembershipCriteria{Operation: operation, Key: key, StringValue: value.text}, nil
+}
+
+// criteriaKey maps attribute.<name> to an identity attribute and account[<source id>].<name> to an account attribute
+func criteriaKey(token criteriaToken) (*identitynow.RoleKey, error) {
+	if strings.HasPrefix(token.text, "attribute.") && len(token.text) > len("attribute.") && !strings.Contains(token.text, "[") {
+		return &identitynow.RoleKey{Type: "IDENTITY", Property: token.text}, nil
+	}
+	if rest, ok := strings.CutPrefix(token.text, "account["); ok {
+		sourceID, name, ok := strings.Cut(rest, "].")
+		if ok && sourceID != "" && name != "" && !strings.ContainsAny(name, "[]") {
+			return &identitynow.RoleKey{Type: "ACCOUNT", Property: "attribute." + name, SourceId: sourceID}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid attribute %q at position %d, expected attribute.<name> or account[<source id>].<name>", token.text, token.position)
+}
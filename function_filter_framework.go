@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
+)
+
+var _ function.Function = &FilterFunction{}
+var _ function.Function = &FilterJoinFunction{}
+
+// filterOperators are the comparison operators of the v2025 filters syntax
+var filterOperators = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true,
+	"co": true, "sw": true, "in": true, "pr": true, "isnull": true,
+}
+
+var filterAttributePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+func NewFilterFunction() function.Function {
+	return &FilterFunction{}
+}
+
+func NewFilterAndFunction() function.Function {
+	return &FilterJoinFunction{name: "filter_and", operator: "and"}
+}
+
+func NewFilterOrFunction() function.Function {
+	return &FilterJoinFunction{name: "filter_or", operator: "or"}
+}
+
+// FilterFunction builds one comparison of a filters expression, quoting and escaping its value
+type FilterFunction struct{}
+
+func (f *FilterFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "filter"
+}
+
+func (f *FilterFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a filters expression comparing an attribute with a value",
+		MarkdownDescription: "Returns a v2025 filters expression such as `name eq \"Admins\"`. Strings are quoted and escaped, " +
+			"lists are rendered for the `in` operator and `pr` and `isnull` take a null value.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "attribute",
+				MarkdownDescription: "Attribute to compare, e.g. `name` or `owner.id`",
+			},
+			function.StringParameter{
+				Name:                "operator",
+				MarkdownDescription: "One of `eq`, `ne`, `gt`, `ge`, `lt`, `le`, `co`, `sw`, `in`, `pr` and `isnull`",
+			},
+			function.DynamicParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to compare with, a string, number, bool or for `in` a list of them",
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FilterFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var attribute, operator string
+	var value types.Dynamic
+	resp.Error = req.Arguments.Get(ctx, &attribute, &operator, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	if !filterAttributePattern.MatchString(attribute) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid attribute %q", attribute))
+		return
+	}
+	operator = strings.ToLower(operator)
+	if !filterOperators[operator] {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("unsupported operator %q", operator))
+		return
+	}
+
+	if operator == "pr" || operator == "isnull" {
+		if !value.IsNull() && !value.IsUnderlyingValueNull() {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("operator %s takes no value, use null", operator))
+			return
+		}
+		resp.Error = resp.Result.Set(ctx, attribute+" "+operator)
+		return
+	}
+	if value.IsNull() || value.IsUnderlyingValueNull() {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("operator %s needs a value", operator))
+		return
+	}
+
+	var rendered string
+	var err error
+	if elements, ok := filterCollectionElements(value.UnderlyingValue()); ok {
+		if operator != "in" {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("operator %s takes a single value, only in takes a list", operator))
+			return
+		}
+		rendered, err = filterList(elements)
+	} else {
+		if operator == "in" {
+			resp.Error = function.NewArgumentFuncError(2, "operator in takes a list")
+			return
+		}
+		rendered, err = filterValue(value.UnderlyingValue())
+	}
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, attribute+" "+operator+" "+rendered)
+}
+
+// filterCollectionElements returns the elements of a list, set or tuple value
+func filterCollectionElements(value attr.Value) ([]attr.Value, bool) {
+	switch v := value.(type) {
+	case types.List:
+		return v.Elements(), true
+	case types.Set:
+		return v.Elements(), true
+	case types.Tuple:
+		return v.Elements(), true
+	}
+	return nil, false
+}
+
+// filterList renders the parenthesized values of an in comparison
+func filterList(elements []attr.Value) (string, error) {
+	if len(elements) == 0 {
+		return "", fmt.Errorf("operator in needs at least one value")
+	}
+	rendered := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if dynamic, ok := element.(types.Dynamic); ok {
+			element = dynamic.UnderlyingValue()
+		}
+		value, err := filterValue(element)
+		if err != nil {
+			return "", err
+		}
+		rendered = append(rendered, value)
+	}
+	return "(" + strings.Join(rendered, ", ") + ")", nil
+}
+
+// filterValue renders a single value of a comparison
+func filterValue(value attr.Value) (string, error) {
+	if value == nil || value.IsNull() {
+		return "", fmt.Errorf("values must not be null")
+	}
+	if value.IsUnknown() {
+		return "", fmt.Errorf("values must be known")
+	}
+	switch v := value.(type) {
+	case types.String:
+		return identitynow.FilterString(v.ValueString()), nil
+	case types.Bool:
+		return fmt.Sprintf("%t", v.ValueBool()), nil
+	case types.Number:
+		return v.ValueBigFloat().Text('f', -1), nil
+	}
+	return "", fmt.Errorf("unsupported value type %s", value.Type(context.Background()))
+}
+
+// FilterJoinFunction combines filters expressions with and or or, grouping compound operands
+type FilterJoinFunction struct {
+	name     string
+	operator string
+}
+
+func (f *FilterJoinFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *FilterJoinFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: fmt.Sprintf("Combines filters expressions with %s", f.operator),
+		MarkdownDescription: fmt.Sprintf("Joins the filters expressions with `%s`. Empty expressions are skipped "+
+			"and operands that are themselves combined are put in parentheses.", f.operator),
+		VariadicParameter: function.StringParameter{
+			Name:                "filters",
+			MarkdownDescription: "Filters expressions, e.g. built by `provider::identitynow::filter`",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FilterJoinFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var filters []string
+	resp.Error = req.Arguments.Get(ctx, &filters)
+	if resp.Error != nil {
+		return
+	}
+
+	var operands []string
+	for _, filter := range filters {
+		filter = strings.TrimSpace(filter)
+		if filter != "" {
+			operands = append(operands, filter)
+		}
+	}
+	if len(operands) > 1 {
+		for i, operand := range operands {
+			if filterIsCompound(operand) {
+				operands[i] = "(" + operand + ")"
+			}
+		}
+	}
+	resp.Error = resp.Result.Set(ctx, strings.Join(operands, " "+f.operator+" "))
+}
+
+// filterIsCompound reports whether an expression combines comparisons outside of quotes and parentheses
+func filterIsCompound(filter string) bool {
+	lower := strings.ToLower(filter)
+	depth := 0
+	quoted := false
+	for i := 0; i < len(lower); i++ {
+		switch {
+		case quoted && lower[i] == '\\':
+			i++
+		case lower[i] == '"':
+			quoted = !quoted
+		case quoted:
+		case lower[i] == '(':
+			depth++
+		case lower[i] == ')':
+			depth--
+		case depth == 0 && (strings.HasPrefix(lower[i:], " and ") || strings.HasPrefix(lower[i:], " or ")):
+			return true
+		case i == 0 && strings.HasPrefix(lower, "not "):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// idIdentityModel is the identity of objects addressed by their id alone
+type idIdentityModel struct {
+	ID types.String `tfsdk:"id"`
+}
+
+func idIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The IdentityNow id of the object",
+			},
+		},
+	}
+}
+
+// setIdentity stores the identity model of an object. Terraform versions without
+// resource identity support send no identity, then there is nothing to set.
+func setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, model interface{}, diags *diag.Diagnostics) {
+	if identity == nil {
+		return
+	}
+	diags.Append(identity.Set(ctx, model)...)
+}
+
+// setIDIdentity stores the identity of an object addressed by id
+func setIDIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, id types.String, diags *diag.Diagnostics) {
+	setIdentity(ctx, identity, idIdentityModel{ID: id}, diags)
+}
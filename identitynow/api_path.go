@@ -0,0 +1,126 @@
+package identitynow
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultAPIVersion is the version prefix used when api_version is not set
+	DefaultAPIVersion = "v2025"
+	// legacyAPIPrefix serves the endpoints that have no versioned equivalent yet
+	legacyAPIPrefix = "cc/api"
+)
+
+var apiVersionPattern = regexp.MustCompile(`^(v[0-9]+|beta)$`)
+
+// apiResource describes an API area, keyed by the first path segment after the version
+type apiResource struct {
+	// experimental endpoints require the X-SailPoint-Experimental header
+	experimental bool
+	// legacy endpoints live under the unversioned /cc/api/ prefix unless overridden
+	legacy bool
+}
+
+// apiResources lists every API area used by the client. api_version_overrides keys must be one of them.
+var apiResources = map[string]apiResource{
+	"sources":           {},
+	"access-profiles":   {},
+	"entitlements":      {},
+	"roles":             {},
+	"identities":        {},
+	"workgroups":        {experimental: true},
+	"source-apps":       {experimental: true},
+	"tagged-objects":    {},
+	"workflows":         {},
+	"password-policies": {},
+	"task-status":       {},
+	// Aggregation schedules: /cc/api/source/getAggregationSchedules and /cc/api/source/scheduleAggregation
+	"source": {legacy: true},
+}
+
+// apiVersions selects the version prefix of every API area
+type apiVersions struct {
+	defaultVersion string
+	overrides      map[string]string
+}
+
+// version returns the path prefix for an API area
+func (v apiVersions) version(resource string) string {
+	if version, ok := v.overrides[resource]; ok {
+		return version
+	}
+	if apiResources[resource].legacy {
+		return legacyAPIPrefix
+	}
+	if v.defaultVersion == "" {
+		return DefaultAPIVersion
+	}
+	return v.defaultVersion
+}
+
+// ValidateAPIVersions checks the provider settings before any client is created
+func ValidateAPIVersions(defaultVersion string, overrides map[string]string) error {
+	if !apiVersionPattern.MatchString(defaultVersion) {
+		return fmt.Errorf("api_version must look like v2025 or beta, got %q", defaultVersion)
+	}
+	for resource, version := range overrides {
+		if _, ok := apiResources[resource]; !ok {
+			return fmt.Errorf("api_version_overrides has unknown API resource %q, expected one of: %s", resource, strings.Join(apiResourceNames(), ", "))
+		}
+		if !apiVersionPattern.MatchString(version) && version != legacyAPIPrefix {
+			return fmt.Errorf("api_version_overrides[%q] must look like v2025 or beta, got %q", resource, version)
+		}
+	}
+	return nil
+}
+
+func apiResourceNames() []string {
+	names := make([]string, 0, len(apiResources))
+	for name := range apiResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apiURL builds the URL of an API area, path escaping each element below it, e.g.
+// apiURL("roles", roleId, "dimensions") returns <base>/v2025/roles/<roleId>/dimensions
+func (c *Client) apiURL(resource string, elems ...string) string {
+	var b strings.Builder
+	b.WriteString(c.BaseURL)
+	b.WriteString("/")
+	b.WriteString(c.apiVersions.version(resource))
+	b.WriteString("/")
+	b.WriteString(resource)
+	for _, elem := range elems {
+		b.WriteString("/")
+		b.WriteString(url.PathEscape(elem))
+	}
+	return b.String()
+}
+
+// setExperimentalHeader opts into experimental endpoints for requests that need it
+func (c *Client) setExperimentalHeader(req *http.Request) {
+	if req.Header.Get("X-SailPoint-Experimental") != "" {
+		return
+	}
+	for resource, r := range apiResources {
+		if !r.experimental {
+			continue
+		}
+		prefix, err := url.Parse(c.apiURL(resource))
+		if err != nil {
+			continue
+		}
+		path := req.URL.EscapedPath()
+		if path == prefix.EscapedPath() || strings.HasPrefix(path, prefix.EscapedPath()+"/") {
+			req.Header.Set("X-SailPoint-Experimental", "true")
+			return
+		}
+	}
+}
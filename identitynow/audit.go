@@ -0,0 +1,178 @@
+package identitynow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	auditLogsMux sync.Mutex
+	auditLogs    = map[string]*AuditLog{}
+)
+
+// AuditLog appends one JSON line per mutating API call. All clients writing to the same path
+// share one AuditLog, so lines of concurrent resource operations never interleave.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// auditEntry is a line of the audit log
+type auditEntry struct {
+	Timestamp    string      `json:"timestamp"`
+	ResourceType string      `json:"resource_type"`
+	ObjectID     string      `json:"object_id,omitempty"`
+	Operation    string      `json:"operation"`
+	Path         string      `json:"path"`
+	Patch        interface{} `json:"patch,omitempty"`
+	Payload      interface{} `json:"payload,omitempty"`
+	Status       int         `json:"status,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	TrackingID   string      `json:"tracking_id,omitempty"`
+	ClientID     string      `json:"client_id,omitempty"`
+	AuthMode     string      `json:"auth_mode"`
+}
+
+// OpenAuditLog returns the audit log writing to path, opening the file on first use
+func OpenAuditLog(path string) (*AuditLog, error) {
+	key := filepath.Clean(path)
+
+	auditLogsMux.Lock()
+	defer auditLogsMux.Unlock()
+
+	if l, ok := auditLogs[key]; ok {
+		return l, nil
+	}
+	file, err := os.OpenFile(key, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit_log_path: %w", err)
+	}
+	l := &AuditLog{file: file}
+	auditLogs[key] = l
+	return l, nil
+}
+
+// Write appends an entry. Each line is a single write on a file opened for appending,
+// so separate Terraform processes sharing the file do not interleave either.
+func (l *AuditLog) Write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(line)
+	return err
+}
+
+// isAuditedMethod reports whether requests with method change the tenant
+func isAuditedMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// audit records a mutating request once its outcome is known. v holds the decoded response,
+// which carries the id of objects created by the request.
+func (c *Client) audit(ctx context.Context, req *http.Request, body []byte, res *http.Response, v interface{}, err error) {
+	resourceType, objectID := c.auditObject(req.URL.Path)
+	if objectID == "" && err == nil {
+		objectID = responseObjectID(v)
+	}
+
+	entry := auditEntry{
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		ResourceType: resourceType,
+		ObjectID:     objectID,
+		Operation:    req.Method,
+		Path:         req.URL.Path,
+		ClientID:     c.clientId,
+		AuthMode:     c.authMode(),
+	}
+
+	if len(body) > 0 {
+		contentType := req.Header.Get("Content-Type")
+		if strings.HasPrefix(contentType, "application/json-patch+json") {
+			entry.Patch = c.redactor.redactBody(body, contentType)
+		} else {
+			entry.Payload = c.redactor.redactBody(body, contentType)
+		}
+	}
+
+	if res != nil {
+		entry.Status = res.StatusCode
+		entry.TrackingID = res.Header.Get("SLPT-Request-ID")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		entry.Status = apiErr.StatusCode
+		if apiErr.TrackingID != "" {
+			entry.TrackingID = apiErr.TrackingID
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if writeErr := c.auditLog.Write(entry); writeErr != nil {
+		tflog.Error(ctx, "Failed to write audit log entry", map[string]interface{}{
+			"error": writeErr.Error(),
+		})
+	}
+}
+
+// auditObject derives the API area and the last object id from a request path, e.g.
+// /v2025/roles/<roleId>/dimensions/<dimensionId> returns roles and <dimensionId>
+func (c *Client) auditObject(requestPath string) (string, string) {
+	if base, err := url.Parse(c.BaseURL); err == nil {
+		requestPath = strings.TrimPrefix(requestPath, strings.TrimSuffix(base.Path, "/"))
+	}
+	requestPath = strings.TrimPrefix(requestPath, "/")
+	requestPath = strings.TrimPrefix(requestPath, legacyAPIPrefix+"/")
+
+	segments := strings.Split(requestPath, "/")
+	if len(segments) > 0 && apiVersionPattern.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return "", ""
+	}
+
+	objectID := ""
+	for _, segment := range segments[1:] {
+		if isIDSegment(segment) {
+			objectID = segment
+		}
+	}
+	return segments[0], objectID
+}
+
+// responseObjectID returns the id of the object in a decoded response, if any
+func responseObjectID(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(data, &object) != nil {
+		return ""
+	}
+	return object.ID
+}
@@ -0,0 +1,97 @@
+package identitynow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	AuthModeClientCredentials = "client_credentials"
+	AuthModeAccessToken       = "access_token"
+	AuthModeTokenFile         = "token_file"
+
+	// tokenFileRecheck is how long a token read from token_file is used before the file is read again,
+	// so a token rotated by a sidecar is picked up without waiting for a 401
+	tokenFileRecheck = 30 * time.Second
+)
+
+// authMode returns how the client obtains its access token
+func (c *Client) authMode() string {
+	switch {
+	case c.accessToken != "":
+		return AuthModeAccessToken
+	case c.tokenFile != "":
+		return AuthModeTokenFile
+	}
+	return AuthModeClientCredentials
+}
+
+// staticAccessToken returns the pre-issued access_token, it can only be used until it expires
+func (c *Client) staticAccessToken(ctx context.Context) (*OauthToken, error) {
+	expiresIn, ok := jwtExpiresIn(c.accessToken, time.Now())
+	if !ok {
+		expiresIn = int(tokenDefaultLifetime / time.Second)
+	}
+	if expiresIn <= 0 {
+		return nil, fmt.Errorf("the configured access_token has expired")
+	}
+
+	tflog.Debug(ctx, "Using pre-issued access token", map[string]interface{}{
+		"expires_in": expiresIn,
+	})
+	return &OauthToken{AccessToken: c.accessToken, TokenType: "bearer", ExpiresIn: expiresIn}, nil
+}
+
+// readTokenFile reads the access token written to token_file by an external process
+func (c *Client) readTokenFile(ctx context.Context) (*OauthToken, error) {
+	data, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token_file %s is empty", c.tokenFile)
+	}
+
+	expiresIn := int(tokenFileRecheck / time.Second)
+	if remaining, ok := jwtExpiresIn(token, time.Now()); ok && remaining < expiresIn {
+		if remaining <= 0 {
+			return nil, fmt.Errorf("the access token in token_file %s has expired", c.tokenFile)
+		}
+		expiresIn = remaining
+	}
+
+	tflog.Debug(ctx, "Read access token from token file", map[string]interface{}{
+		"token_file": c.tokenFile,
+		"expires_in": expiresIn,
+	})
+	return &OauthToken{AccessToken: token, TokenType: "bearer", ExpiresIn: expiresIn}, nil
+}
+
+// jwtExpiresIn returns the seconds left until the exp claim of a JWT access token.
+// The signature is not verified, the claim is only used to know when to stop using the token.
+func jwtExpiresIn(token string, now time.Time) (int, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return 0, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return 0, false
+	}
+	return int(time.Unix(claims.Exp, 0).Sub(now) / time.Second), true
+}
@@ -0,0 +1,264 @@
+package identitynow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// cassetteSecretFragments are redacted from cassettes wherever a key contains them, e.g. bindPassword or sharedSecret.
+// Cassettes are committed as fixtures, so they are scrubbed more broadly than logs.
+var cassetteSecretFragments = []string{"password", "secret"}
+
+var (
+	cassettesMux sync.Mutex
+	cassettes    = map[string]*cassette{}
+)
+
+// cassetteInteraction is a recorded request/response pair. The request URL is stored
+// without scheme and host, so a cassette recorded against one tenant replays against any api_url.
+// Bodies that are not JSON are kept as text.
+type cassetteInteraction struct {
+	Method       string            `json:"method"`
+	URL          string            `json:"url"`
+	Request      json.RawMessage   `json:"request_body,omitempty"`
+	RequestText  string            `json:"request_text,omitempty"`
+	Status       int               `json:"status"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Response     json.RawMessage   `json:"response_body,omitempty"`
+	ResponseText string            `json:"response_text,omitempty"`
+}
+
+type cassetteFile struct {
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+// cassette is shared by every client of the process that uses the same file
+type cassette struct {
+	path string
+	mode string
+
+	mu           sync.Mutex
+	interactions []*cassetteInteraction
+	// replay position per request key, so repeated calls return the recorded sequence
+	next map[string]int
+}
+
+// CassetteTransportFromEnv returns a record or replay transport when IDENTITYNOW_CASSETTE_MODE
+// is set, using the cassette file named by IDENTITYNOW_CASSETTE. Otherwise it returns next unchanged.
+// Recorded secrets are redacted by redactor, nil uses the default rules.
+func CassetteTransportFromEnv(next http.RoundTripper, redactor *Redactor) (http.RoundTripper, error) {
+	mode := strings.ToLower(os.Getenv("IDENTITYNOW_CASSETTE_MODE"))
+	if mode == "" {
+		return next, nil
+	}
+	if mode != CassetteModeRecord && mode != CassetteModeReplay {
+		return nil, fmt.Errorf("IDENTITYNOW_CASSETTE_MODE must be %q or %q, got %q", CassetteModeRecord, CassetteModeReplay, mode)
+	}
+
+	path := os.Getenv("IDENTITYNOW_CASSETTE")
+	if path == "" {
+		return nil, fmt.Errorf("IDENTITYNOW_CASSETTE must name a cassette file when IDENTITYNOW_CASSETTE_MODE is set")
+	}
+
+	c, err := openCassette(path, mode)
+	if err != nil {
+		return nil, err
+	}
+	if redactor == nil {
+		redactor = defaultRedactor
+	}
+	return &cassetteTransport{cassette: c, next: next, redactor: redactor.withKeyFragments(cassetteSecretFragments...)}, nil
+}
+
+func openCassette(path string, mode string) (*cassette, error) {
+	cassettesMux.Lock()
+	defer cassettesMux.Unlock()
+
+	key := mode + ":" + path
+	if c, ok := cassettes[key]; ok {
+		return c, nil
+	}
+
+	c := &cassette{path: path, mode: mode, next: map[string]int{}}
+	if mode == CassetteModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette: %w", err)
+		}
+		var file cassetteFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		c.interactions = file.Interactions
+	}
+
+	cassettes[key] = c
+	return c, nil
+}
+
+// cassetteTransport records interactions to, or replays them from, a cassette
+type cassetteTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+	redactor *Redactor
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	if t.cassette.mode == CassetteModeReplay {
+		return t.cassette.replay(t.redactor, req)
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	if err := t.cassette.record(t.redactor, req, reqBody, res, resBody); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *cassette) record(redactor *Redactor, req *http.Request, reqBody []byte, res *http.Response, resBody []byte) error {
+	interaction := &cassetteInteraction{
+		Method:  req.Method,
+		URL:     cassetteURL(redactor, req.URL),
+		Status:  res.StatusCode,
+		Headers: map[string]string{},
+	}
+	interaction.Request, interaction.RequestText = scrubCassetteBody(redactor, reqBody, req.Header.Get("Content-Type"))
+	interaction.Response, interaction.ResponseText = scrubCassetteBody(redactor, resBody, res.Header.Get("Content-Type"))
+	for _, name := range []string{"Content-Type", "X-Total-Count", "Retry-After"} {
+		if v := res.Header.Get(name); v != "" {
+			interaction.Headers[name] = v
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+
+	// The provider process has no shutdown hook, so the whole cassette is rewritten on every interaction
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+func (c *cassette) replay(redactor *Redactor, req *http.Request) (*http.Response, error) {
+	method, target := req.Method, cassetteURL(redactor, req.URL)
+	key := method + " " + target
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := 0
+	for _, interaction := range c.interactions {
+		if interaction.Method != method || interaction.URL != target {
+			continue
+		}
+		if seen < c.next[key] {
+			seen++
+			continue
+		}
+		c.next[key]++
+
+		header := http.Header{}
+		for name, value := range interaction.Headers {
+			header.Set(name, value)
+		}
+		body := []byte(interaction.Response)
+		if interaction.ResponseText != "" {
+			body = []byte(interaction.ResponseText)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no recorded interaction left for %s", c.path, key)
+}
+
+// cassetteURL returns the path and query of u with secrets redacted and parameters sorted
+func cassetteURL(redactor *Redactor, u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		if redactor.matches([]string{name}) {
+			query.Set(name, redactedValue)
+		}
+	}
+	if len(query) == 0 {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + query.Encode()
+}
+
+// scrubCassetteBody redacts secrets from a body with the same rules as logged payloads, so JSON Patch
+// values are matched by their operation path. JSON bodies are returned as JSON, anything else as text
+// with form encoded secrets redacted.
+func scrubCassetteBody(redactor *Redactor, body []byte, contentType string) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ""
+	}
+
+	if json.Valid(body) {
+		if scrubbed, err := json.Marshal(redactor.redactBody(body, contentType)); err == nil {
+			return scrubbed, ""
+		}
+	}
+
+	text := string(body)
+	if form, err := url.ParseQuery(text); err == nil && len(form) > 0 {
+		for name := range form {
+			if redactor.matches([]string{name}) {
+				form.Set(name, redactedValue)
+			}
+		}
+		text = form.Encode()
+	}
+	return nil, text
+}
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 import (
 	"bytes"
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -20,23 +21,30 @@ type Client struct {
 	BaseURL      string
 	clientId     string
 	clientSecret string
-	accessToken  string
 	HTTPClient   *http.Client
-	tokenExpiry  time.Time
+	tokens       *tokenSource
+	health       *credentialHealth
 	loggerCtx    context.Context
 	rateLimiter  *rate.Limiter
+	tenantLimit  *adaptiveRateLimiter
+	maxRetries   int
+	apiVersions  apiVersions
+	accessToken  string
+	tokenFile    string
+	auditLog     *AuditLog
+	redactor     *Redactor
+	lookups      *lookups
+	taskPollMin  time.Duration
+	readOnly     bool
 }
 
 type errorResponse struct {
-	DetailCode string `json:"detailCode"`
-	Messages   []struct {
-		Locale       string `json:"locale"`
-		LocaleOrigen string `json:"localeOrigin"`
-		Text         string `json:"text"`
-	} `json:"messages"`
+	DetailCode string            `json:"detailCode"`
+	TrackingID string            `json:"trackingId"`
+	Messages   []APIErrorMessage `json:"messages"`
 }
 
-func NewClient(ctx context.Context, baseURL string, clientId string, secret string, rateLimit int) *Client {
+func NewClient(ctx context.Context, baseURL string, clientId string, secret string, rateLimit int, maxRetries int) *Client {
 	subctx := tflog.NewSubsystem(ctx, "identitynow")
 	// Mask the client_secret if it ever appears as a field
 	subctx = tflog.MaskFieldValuesWithFieldKeys(subctx, "client_secret")
@@ -47,25 +55,48 @@ func NewClient(ctx context.Context, baseURL string, clientId string, secret stri
 	// Create rate limiter: [rateLimit] requests per second with burst of 1
 	limiter := rate.NewLimiter(rate.Limit(rateLimit), 1)
 
-	return &Client{
+	c := &Client{
 		BaseURL:      baseURL,
 		clientId:     clientId,
 		clientSecret: secret,
 		loggerCtx:    subctx,
 		rateLimiter:  limiter,
+		tenantLimit:  tenantRateLimiter(baseURL),
+		maxRetries:   maxRetries,
+		apiVersions:  apiVersions{defaultVersion: DefaultAPIVersion},
+		redactor:     defaultRedactor,
+		taskPollMin:  defaultTaskPollMin,
 		HTTPClient: &http.Client{
-			Timeout: time.Minute,
+			Timeout: DefaultHTTPTimeout,
 		},
 	}
+	c.tokens = newTokenSource(subctx, c.requestToken)
+	c.health = newCredentialHealth()
+
+	return c
 }
 
+// GetToken forces a refresh of the client's access token
 func (c *Client) GetToken(ctx context.Context) error {
+	_, err := c.tokens.Refresh(ctx)
+	return err
+}
+
+// requestToken obtains a new access token for the configured authentication mode
+func (c *Client) requestToken(ctx context.Context) (*OauthToken, error) {
+	switch c.authMode() {
+	case AuthModeAccessToken:
+		return c.staticAccessToken(ctx)
+	case AuthModeTokenFile:
+		return c.readTokenFile(ctx)
+	}
+
 	// Apply rate limiting before making any API requests
 	if err := c.rateLimiter.Wait(ctx); err != nil {
 		tflog.Debug(ctx, "Rate limiting wait failed", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return fmt.Errorf("rate limiting failed: %w", err)
+		return nil, fmt.Errorf("rate limiting failed: %w", err)
 	}
 
 	tflog.Debug(ctx, "Obtaining OAuth token from IdentityNow", map[string]interface{}{
@@ -73,16 +104,22 @@ func (c *Client) GetToken(ctx context.Context) error {
 		"client_id": c.clientId,
 	})
 
-	tokenURL := fmt.Sprintf("%s/oauth/token?grant_type=client_credentials&client_id=%s&client_secret=%s", c.BaseURL, c.clientId, c.clientSecret)
+	// Credentials, including personal access tokens, go in the form encoded body, never in the URL
+	tokenURL := c.BaseURL + "/oauth/token"
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientId)
+	form.Set("client_secret", c.clientSecret)
 	tflog.Debug(ctx, "Creating HTTP request for OAuth token", map[string]interface{}{
 		"method": "POST",
-		"url":    c.BaseURL + "/oauth/token", // Don't log credentials in URL
+		"url":    tokenURL,
 	})
-	req, err := http.NewRequest("POST", tokenURL, nil)
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 	req = req.WithContext(ctx)
 
@@ -92,20 +129,17 @@ func (c *Client) GetToken(ctx context.Context) error {
 		tflog.Error(ctx, "Failed to get OAuth token", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		var errRes errorResponse
-		err = json.NewDecoder(res.Body).Decode(&errRes)
-		if err == nil && len(errRes.Messages) > 0 {
-			return errors.New(errRes.Messages[0].Text)
-		}
+		apiErr := newAPIError(req.Method, tokenURL, res)
 		tflog.Debug(ctx, "Failed to get OAuth token", map[string]interface{}{
 			"status_code": res.StatusCode,
+			"tracking_id": apiErr.TrackingID,
 		})
-		return fmt.Errorf("failed to get token, status code: %d", res.StatusCode)
+		return nil, apiErr
 	}
 
 	var tokenRes OauthToken
@@ -113,7 +147,7 @@ func (c *Client) GetToken(ctx context.Context) error {
 		tflog.Error(ctx, "Failed to decode OAuth token response", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return err
+		return nil, err
 	}
 
 	tflog.Debug(ctx, "OAuth token response received", map[string]interface{}{
@@ -121,61 +155,36 @@ func (c *Client) GetToken(ctx context.Context) error {
 		"token_type": tokenRes.TokenType,
 	})
 
-	c.accessToken = tokenRes.AccessToken
-
-	// Set expiry with a safety margin and better validation
-	if tokenRes.ExpiresIn > 0 {
-		expirationDuration := time.Duration(tokenRes.ExpiresIn) * time.Second
-		// Subtract 5 minutes as safety margin to refresh before actual expiry
-		safetyMargin := 5 * time.Minute
-		if expirationDuration > safetyMargin {
-			expirationDuration -= safetyMargin
-		}
-		c.tokenExpiry = time.Now().Add(expirationDuration)
-
-		tflog.Debug(ctx, "Token expiry set", map[string]interface{}{
-			"expires_in_seconds": tokenRes.ExpiresIn,
-			"token_expiry":       c.tokenExpiry.Format(time.RFC3339),
-		})
-	} else {
-		// Fallback: set a default expiry of 1 hour if expires_in is missing or invalid
-		c.tokenExpiry = time.Now().Add(1 * time.Hour)
-		tflog.Warn(ctx, "expires_in field missing or invalid, using default 1 hour expiry", map[string]interface{}{
-			"expires_in_received": tokenRes.ExpiresIn,
-			"default_expiry":      c.tokenExpiry.Format(time.RFC3339),
-		})
+	if len(tokenRes.AccessToken) == 0 {
+		return nil, errors.New("access token is empty in OAuth token response")
 	}
 
-	return nil
+	return &tokenRes, nil
 }
 
 func (c *Client) GetSourceByName(ctx context.Context, name string) ([]*Source, error) {
-	filter := fmt.Sprintf("name eq \"%s\"", name)
-	sourceURL := fmt.Sprintf("%s/v2025/sources?filters=%s", c.BaseURL, url.QueryEscape(filter))
-	tflog.Debug(ctx, "Creating HTTP request to get source", map[string]interface{}{
-		"method":      "GET",
+	filter := "name eq " + FilterString(name)
+	sourceURL := fmt.Sprintf("%s?filters=%s", c.apiURL("sources"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing sources by name", map[string]interface{}{
 		"url":         sourceURL,
 		"source_name": name,
 	})
-	req, err := http.NewRequest("GET", sourceURL, nil)
-	if err != nil {
-		return nil, err
-	}
 
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-
-	req = req.WithContext(ctx)
+	return listAll(ctx, c, sourceURL, listOptions[*Source]{})
+}
 
-	var res []*Source
-	if err := c.sendRequest(ctx, req, &res); err != nil {
-		return nil, err
-	}
+// ListSources returns every source matching filters, e.g. connector eq "delimited-file", or all sources when filters is empty
+func (c *Client) ListSources(ctx context.Context, filters string) ([]*Source, error) {
+	sourceURL := withFilters(c.apiURL("sources"), filters)
+	tflog.Debug(ctx, "Listing sources", map[string]interface{}{
+		"url": sourceURL,
+	})
 
-	return res, nil
+	return listAll(ctx, c, sourceURL, listOptions[*Source]{})
 }
 
 func (c *Client) GetSource(ctx context.Context, id string) (*Source, error) {
-	sourceURL := fmt.Sprintf("%s/v2025/sources/%s", c.BaseURL, id)
+	sourceURL := c.apiURL("sources", id)
 	tflog.Debug(ctx, "Creating HTTP request to get source", map[string]interface{}{
 		"method":    "GET",
 		"url":       sourceURL,
@@ -203,7 +212,7 @@ func (c *Client) CreateSourceRequest(ctx context.Context, source *Source) (*Sour
 	if err != nil {
 		return nil, err
 	}
-	sourceURL := fmt.Sprintf("%s/v2025/sources", c.BaseURL)
+	sourceURL := c.apiURL("sources")
 	tflog.Debug(ctx, "Creating HTTP request to create source", map[string]interface{}{
 		"method": "POST",
 		"url":    sourceURL,
@@ -225,7 +234,7 @@ func (c *Client) CreateSourceRequest(ctx context.Context, source *Source) (*Sour
 	if err := c.sendRequest(ctx, req, &res); err != nil {
 		tflog.Error(ctx, "Failed source creation", map[string]interface{}{
 			"error":    err.Error(),
-			"response": fmt.Sprintf("%+v", res),
+			"response": c.redactor.loggable(res),
 		})
 		return nil, err
 	}
@@ -278,12 +287,9 @@ func (c *Client) AddConnectorAttributesToMicrosoftEntraSource(ctx context.Contex
 		})
 		return nil, fmt.Errorf("failed to marshal updateSource: %w", err)
 	}
-	tflog.Debug(ctx, "UpdateSource request body", map[string]interface{}{
-		"body": string(body),
-	})
-
+	// The patch carries client_secret, sendRequest logs it through the redactor at TRACE
 	// Create the HTTP PATCH request
-	patchURL := fmt.Sprintf("%s/v2025/sources/%s", c.BaseURL, source.ID)
+	patchURL := c.apiURL("sources", source.ID)
 	tflog.Debug(ctx, "Creating HTTP request to add connector attributes to Microsoft Entra source", map[string]interface{}{
 		"method":    "PATCH",
 		"url":       patchURL,
@@ -309,11 +315,6 @@ func (c *Client) AddConnectorAttributesToMicrosoftEntraSource(ctx context.Contex
 		return nil, fmt.Errorf("failed to update source: %w", err)
 	}
 
-	resBody, _ := json.MarshalIndent(res, "", "  ")
-	tflog.Debug(ctx, "Response body received", map[string]interface{}{
-		"response": string(resBody),
-	})
-
 	return &res, nil
 }
 
@@ -351,7 +352,7 @@ func (c *Client) UpdateSource(ctx context.Context, source *Source) (*Source, err
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/sources/%s", c.BaseURL, source.ID)
+	updateURL := c.apiURL("sources", source.ID)
 	tflog.Debug(ctx, "Creating HTTP request to update source", map[string]interface{}{
 		"method":    "PUT",
 		"url":       updateURL,
@@ -374,7 +375,7 @@ func (c *Client) UpdateSource(ctx context.Context, source *Source) (*Source, err
 	if err := c.sendRequest(ctx, req, &res); err != nil {
 		tflog.Error(ctx, "Failed source update", map[string]interface{}{
 			"error":    err.Error(),
-			"response": fmt.Sprintf("%+v", res),
+			"response": c.redactor.loggable(res),
 		})
 		return nil, err
 	}
@@ -383,7 +384,7 @@ func (c *Client) UpdateSource(ctx context.Context, source *Source) (*Source, err
 }
 
 func (c *Client) DeleteSource(ctx context.Context, source *Source) error {
-	deleteURL := fmt.Sprintf("%s/v2025/sources/%s", c.BaseURL, source.ID)
+	deleteURL := c.apiURL("sources", source.ID)
 	tflog.Debug(ctx, "Creating HTTP request to delete source", map[string]interface{}{
 		"method":    "DELETE",
 		"url":       deleteURL,
@@ -401,166 +402,124 @@ func (c *Client) DeleteSource(ctx context.Context, source *Source) error {
 
 	req = req.WithContext(ctx)
 
-	var res interface{}
+	res := TaskResult{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
 		tflog.Error(ctx, "Failed source deletion", map[string]interface{}{
 			"error":    err.Error(),
-			"response": fmt.Sprintf("%+v", res),
+			"response": c.redactor.loggable(res),
 		})
 		return err
 	}
 
-	return nil
+	// The source is removed by a background task, wait for it so destroy only completes once it is gone
+	if res.ID != "" {
+		tflog.Debug(ctx, "Waiting for source deletion task", map[string]interface{}{
+			"source_id": source.ID,
+			"task_id":   res.ID,
+		})
+		if _, err := c.WaitForTask(ctx, res.ID); err != nil {
+			return err
+		}
+	}
+	return c.waitForDeletion(ctx, func(ctx context.Context) error {
+		_, err := c.GetSource(ctx, source.ID)
+		return err
+	})
 }
 
 func (c *Client) GetAccessProfileByName(ctx context.Context, name string) ([]*AccessProfile, error) {
-	filter := fmt.Sprintf("name eq \"%s\"", name)
-	profileURL := fmt.Sprintf("%s/v2025/access-profiles?filters=%s", c.BaseURL, url.QueryEscape(filter))
-	tflog.Debug(ctx, "Creating HTTP request to get access profile", map[string]interface{}{
-		"method": "GET",
-		"url":    profileURL,
-		"name":   name,
+	filter := "name eq " + FilterString(name)
+	profileURL := fmt.Sprintf("%s?filters=%s", c.apiURL("access-profiles"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing access profiles by name", map[string]interface{}{
+		"url":  profileURL,
+		"name": name,
 	})
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", profileURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create HTTP request for access profile", map[string]interface{}{
-				"name":  name,
-				"error": err.Error(),
-			})
-			return nil, err
-		}
 
-		req = req.WithContext(ctx)
-
-		res := []*AccessProfile{}
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Failed to get access profile", map[string]interface{}{
-				"name":  name,
-				"error": err.Error(),
-			})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
-		tflog.Debug(ctx, "Successfully retrieved access profile", map[string]interface{}{
-			"name": name,
+	res, err := listAll(ctx, c, profileURL, listOptions[*AccessProfile]{})
+	if err != nil {
+		tflog.Error(ctx, "Failed to get access profile", map[string]interface{}{
+			"name":  name,
+			"error": err.Error(),
 		})
-
-		return res, nil
+		return nil, err
 	}
-	return nil, errors.New("dead code")
+	tflog.Debug(ctx, "Successfully retrieved access profile", map[string]interface{}{
+		"name": name,
+	})
+
+	return res, nil
+}
+
+// ListAccessProfiles returns every access profile matching filters, or all access profiles when filters is empty
+func (c *Client) ListAccessProfiles(ctx context.Context, filters string) ([]*AccessProfile, error) {
+	profileURL := withFilters(c.apiURL("access-profiles"), filters)
+	tflog.Debug(ctx, "Listing access profiles", map[string]interface{}{
+		"url": profileURL,
+	})
+
+	return listAll(ctx, c, profileURL, listOptions[*AccessProfile]{})
 }
 
 func (c *Client) GetAccessProfile(ctx context.Context, id string) (*AccessProfile, error) {
-	profileURL := fmt.Sprintf("%s/v2025/access-profiles/%s", c.BaseURL, id)
+	profileURL := c.apiURL("access-profiles", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to get access profile", map[string]interface{}{
 		"method":     "GET",
 		"url":        profileURL,
 		"profile_id": id,
 	})
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", profileURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create HTTP request for access profile", map[string]interface{}{
-				"profile_id": id,
-				"error":      err.Error(),
-			})
-			return nil, err
-		}
+	req, err := http.NewRequest("GET", profileURL, nil)
+	if err != nil {
+		tflog.Error(ctx, "Failed to create HTTP request for access profile", map[string]interface{}{
+			"profile_id": id,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
 
-		req = req.WithContext(ctx)
+	req = req.WithContext(ctx)
 
-		res := AccessProfile{}
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Failed to get access profile", map[string]interface{}{
-				"profile_id": id,
-				"error":      err.Error(),
-			})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
-		tflog.Debug(ctx, "Successfully retrieved access profile", map[string]interface{}{
+	res := AccessProfile{}
+	if err := c.sendRequest(ctx, req, &res); err != nil {
+		tflog.Error(ctx, "Failed to get access profile", map[string]interface{}{
 			"profile_id": id,
+			"error":      err.Error(),
 		})
-
-		return &res, nil
+		return nil, err
 	}
-	return nil, errors.New("dead code")
+	tflog.Debug(ctx, "Successfully retrieved access profile", map[string]interface{}{
+		"profile_id": id,
+	})
+
+	return &res, nil
 }
 
 func (c *Client) GetSourceEntitlements(ctx context.Context, id string) ([]*SourceEntitlement, error) {
-	entitlementsURL := fmt.Sprintf("%s/v2025/entitlements?filters=source.id", c.BaseURL) + url.QueryEscape(" eq ") + fmt.Sprintf("\"%s\"", id)
-	tflog.Debug(ctx, "Creating HTTP request to get source entitlements", map[string]interface{}{
-		"method":    "GET",
+	filter := "source.id eq " + FilterString(id)
+	entitlementsURL := fmt.Sprintf("%s?filters=%s", c.apiURL("entitlements"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing source entitlements", map[string]interface{}{
 		"url":       entitlementsURL,
 		"source_id": id,
 	})
-	req, err := http.NewRequest("GET", entitlementsURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-		return nil, err
-	}
 
-	req = req.WithContext(ctx)
+	return listAll(ctx, c, entitlementsURL, listOptions[*SourceEntitlement]{})
+}
 
-	var res []*SourceEntitlement
-	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-		// Error already logged above
-		return nil, err
+func (c *Client) GetSourceEntitlement(ctx context.Context, id string, nameFilter string) ([]*SourceEntitlement, error) {
+	if c.lookups != nil {
+		cacheKey := "entitlement:" + id + ":" + strings.ToLower(nameFilter)
+		return cachedLookup(ctx, c.lookups, cacheKey, c.lookups.entitlementBatcher(id), nameFilter)
 	}
-	return res, nil
+	return c.listEntitlements(ctx, fmt.Sprintf("source.id eq %s and (name eq %s)", FilterString(id), FilterString(nameFilter)))
 }
 
-func (c *Client) GetSourceEntitlement(ctx context.Context, id string, nameFilter string) ([]*SourceEntitlement, error) {
-	filter := fmt.Sprintf("source.id eq \"%s\" and (name eq \"%s\")", id, nameFilter)
-	entitlementURL := fmt.Sprintf("%s/v2025/entitlements?filters=%s", c.BaseURL, url.QueryEscape(filter))
-	tflog.Debug(ctx, "Creating HTTP request to get source entitlement", map[string]interface{}{
-		"method":      "GET",
-		"url":         entitlementURL,
-		"source_id":   id,
-		"name_filter": nameFilter,
+func (c *Client) listEntitlements(ctx context.Context, filter string) ([]*SourceEntitlement, error) {
+	entitlementURL := fmt.Sprintf("%s?filters=%s", c.apiURL("entitlements"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing source entitlements by filter", map[string]interface{}{
+		"url":    entitlementURL,
+		"filter": filter,
 	})
 
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", entitlementURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error(), "attempt": attempt})
-			return nil, err
-		}
-
-		req = req.WithContext(ctx)
-
-		var res []*SourceEntitlement
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
-		return res, nil
-	}
-	return nil, errors.New("dead code")
+	return listAll(ctx, c, entitlementURL, listOptions[*SourceEntitlement]{})
 }
 
 func (c *Client) CreateAccessProfile(ctx context.Context, accessProfile *AccessProfile) (*AccessProfile, error) {
@@ -569,7 +528,7 @@ func (c *Client) CreateAccessProfile(ctx context.Context, accessProfile *AccessP
 		return nil, err
 	}
 
-	createURL := fmt.Sprintf("%s/v2025/access-profiles", c.BaseURL)
+	createURL := c.apiURL("access-profiles")
 	tflog.Debug(ctx, "Creating HTTP request to create access profile", map[string]interface{}{
 		"method": "POST",
 		"url":    createURL,
@@ -587,7 +546,7 @@ func (c *Client) CreateAccessProfile(ctx context.Context, accessProfile *AccessP
 
 	res := AccessProfile{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -600,7 +559,7 @@ func (c *Client) UpdateAccessProfile(ctx context.Context, accessProfile []*Updat
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/access-profiles/%s", c.BaseURL, id)
+	updateURL := c.apiURL("access-profiles", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to update access profile", map[string]interface{}{
 		"method":     "PATCH",
 		"url":        updateURL,
@@ -619,7 +578,7 @@ func (c *Client) UpdateAccessProfile(ctx context.Context, accessProfile []*Updat
 
 	res := AccessProfile{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -628,7 +587,7 @@ func (c *Client) UpdateAccessProfile(ctx context.Context, accessProfile []*Updat
 }
 
 func (c *Client) DeleteAccessProfile(ctx context.Context, accessProfile *AccessProfile) error {
-	deleteURL := fmt.Sprintf("%s/v2025/access-profiles/%s", c.BaseURL, accessProfile.ID)
+	deleteURL := c.apiURL("access-profiles", accessProfile.ID)
 	tflog.Debug(ctx, "Creating HTTP request to delete access profile", map[string]interface{}{
 		"method":     "DELETE",
 		"url":        deleteURL,
@@ -646,7 +605,7 @@ func (c *Client) DeleteAccessProfile(ctx context.Context, accessProfile *AccessP
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -655,7 +614,7 @@ func (c *Client) DeleteAccessProfile(ctx context.Context, accessProfile *AccessP
 }
 
 func (c *Client) GetRole(ctx context.Context, id string) (*Role, error) {
-	roleURL := fmt.Sprintf("%s/v2025/roles/%s", c.BaseURL, id)
+	roleURL := c.apiURL("roles", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to get role", map[string]interface{}{
 		"method":  "GET",
 		"url":     roleURL,
@@ -673,7 +632,7 @@ func (c *Client) GetRole(ctx context.Context, id string) (*Role, error) {
 
 	res := Role{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -681,13 +640,23 @@ func (c *Client) GetRole(ctx context.Context, id string) (*Role, error) {
 	return &res, nil
 }
 
+// ListRoles returns every role matching filters, or all roles when filters is empty
+func (c *Client) ListRoles(ctx context.Context, filters string) ([]*Role, error) {
+	roleURL := withFilters(c.apiURL("roles"), filters)
+	tflog.Debug(ctx, "Listing roles", map[string]interface{}{
+		"url": roleURL,
+	})
+
+	return listAll(ctx, c, roleURL, listOptions[*Role]{})
+}
+
 func (c *Client) CreateRole(ctx context.Context, role *Role) (*Role, error) {
 	body, err := json.Marshal(&role)
 	if err != nil {
 		return nil, err
 	}
 
-	createURL := fmt.Sprintf("%s/v2025/roles", c.BaseURL)
+	createURL := c.apiURL("roles")
 	tflog.Debug(ctx, "Creating HTTP request to create role", map[string]interface{}{
 		"method": "POST",
 		"url":    createURL,
@@ -698,7 +667,7 @@ func (c *Client) CreateRole(ctx context.Context, role *Role) (*Role, error) {
 		return nil, err
 	}
 	tflog.Debug(ctx, "Role request details", map[string]interface{}{
-		"request": fmt.Sprintf("%v", req),
+		"request_body": c.redactor.redactBody(body, "application/json"),
 	})
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
@@ -708,7 +677,7 @@ func (c *Client) CreateRole(ctx context.Context, role *Role) (*Role, error) {
 
 	res := Role{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -721,7 +690,7 @@ func (c *Client) UpdateRole(ctx context.Context, role []*UpdateRole, id interfac
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/roles/%s", c.BaseURL, id)
+	updateURL := c.apiURL("roles", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to update role", map[string]interface{}{
 		"method":  "PATCH",
 		"url":     updateURL,
@@ -740,7 +709,7 @@ func (c *Client) UpdateRole(ctx context.Context, role []*UpdateRole, id interfac
 
 	res := Role{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -753,7 +722,7 @@ func (c *Client) DeleteRole(ctx context.Context, role *Role) (*Role, error) {
 	if err != nil {
 		return nil, err
 	}
-	deleteURL := fmt.Sprintf("%s/v2025/roles/%s", c.BaseURL, role.ID)
+	deleteURL := c.apiURL("roles", role.ID)
 	tflog.Debug(ctx, "Creating HTTP request to delete role", map[string]interface{}{
 		"method":  "DELETE",
 		"url":     deleteURL,
@@ -771,7 +740,7 @@ func (c *Client) DeleteRole(ctx context.Context, role *Role) (*Role, error) {
 
 	res := Role{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -780,78 +749,38 @@ func (c *Client) DeleteRole(ctx context.Context, role *Role) (*Role, error) {
 }
 
 func (c *Client) GetIdentityByAlias(ctx context.Context, alias string) ([]*Identity, error) {
-	identityURL := fmt.Sprintf("%s/v2025/identities?filters=alias", c.BaseURL) + url.QueryEscape(" eq ") + fmt.Sprintf("\"%s\"", alias)
-	tflog.Debug(ctx, "Creating HTTP request to get identity by alias", map[string]interface{}{
-		"method": "GET",
-		"url":    identityURL,
-		"alias":  alias,
-	})
-	req, err := http.NewRequest("GET", identityURL, nil)
-
-	if err != nil {
-		tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-		return nil, err
+	if c.lookups != nil {
+		return cachedLookup(ctx, c.lookups, "identity-alias:"+strings.ToLower(alias), c.lookups.identitiesByAlias, alias)
 	}
-	tflog.Debug(ctx, "GetIdentity request details", map[string]interface{}{"request": fmt.Sprintf("%+v", req)})
-
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-
-	req = req.WithContext(ctx)
+	return c.listIdentities(ctx, "alias eq "+FilterString(alias))
+}
 
-	var res []*Identity
-	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-		// Error already logged above
-		return nil, err
+func (c *Client) GetIdentityByEmail(ctx context.Context, email string) ([]*Identity, error) {
+	if c.lookups != nil {
+		return cachedLookup(ctx, c.lookups, "identity-email:"+strings.ToLower(email), c.lookups.identitiesByEmail, email)
 	}
-
-	tflog.Debug(ctx, "GetIdentity response details", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-
-	return res, nil
+	return c.listIdentities(ctx, "email eq "+FilterString(email))
 }
 
-func (c *Client) GetIdentityByEmail(ctx context.Context, email string) ([]*Identity, error) {
-	identityURL := fmt.Sprintf("%s/v2025/identities?filters=email", c.BaseURL) + url.QueryEscape(" eq ") + fmt.Sprintf("\"%s\"", email)
-	tflog.Debug(ctx, "Creating HTTP request to get identity by email", map[string]interface{}{
-		"method": "GET",
+func (c *Client) listIdentities(ctx context.Context, filter string) ([]*Identity, error) {
+	identityURL := fmt.Sprintf("%s?filters=%s", c.apiURL("identities"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing identities by filter", map[string]interface{}{
 		"url":    identityURL,
-		"email":  email,
+		"filter": filter,
 	})
 
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", identityURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
-		tflog.Debug(ctx, "GetIdentity request details", map[string]interface{}{"request": fmt.Sprintf("%+v", req)})
-
-		req.Header.Set("Accept", "application/json; charset=utf-8")
+	res, err := listAll(ctx, c, identityURL, listOptions[*Identity]{})
+	if err != nil {
+		return nil, err
+	}
 
-		req = req.WithContext(ctx)
+	tflog.Debug(ctx, "GetIdentity response details", map[string]interface{}{"response": c.redactor.loggable(res)})
 
-		var res []*Identity
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
-		tflog.Debug(ctx, "GetIdentity response details", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-
-		return res, nil
-	}
-	return nil, errors.New("dead code")
+	return res, nil
 }
 
 func (c *Client) GetAccountAggregationSchedule(ctx context.Context, id string) (*AccountAggregationSchedule, error) {
-	scheduleURL := fmt.Sprintf("%s/cc/api/source/getAggregationSchedules/%s", c.BaseURL, id)
+	scheduleURL := c.apiURL("source", "getAggregationSchedules", id)
 	tflog.Debug(ctx, "Creating HTTP request to get account aggregation schedule", map[string]interface{}{
 		"method":    "GET",
 		"url":       scheduleURL,
@@ -867,7 +796,7 @@ func (c *Client) GetAccountAggregationSchedule(ctx context.Context, id string) (
 
 	res := []AccountAggregationSchedule{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -876,7 +805,7 @@ func (c *Client) GetAccountAggregationSchedule(ctx context.Context, id string) (
 }
 
 func (c *Client) ManageAccountAggregationSchedule(ctx context.Context, scheduleAggregation *AccountAggregationSchedule, enable bool) (*AccountAggregationSchedule, error) {
-	endpoint := fmt.Sprintf("%s/cc/api/source/scheduleAggregation/%s", c.BaseURL, scheduleAggregation.SourceID)
+	endpoint := c.apiURL("source", "scheduleAggregation", scheduleAggregation.SourceID)
 	data := url.Values{}
 	data.Set("enable", fmt.Sprintf("%t", enable))
 	data.Set("cronExp", scheduleAggregation.CronExpressions[0])
@@ -898,7 +827,7 @@ func (c *Client) ManageAccountAggregationSchedule(ctx context.Context, scheduleA
 
 	res := AccountAggregationSchedule{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -907,7 +836,7 @@ func (c *Client) ManageAccountAggregationSchedule(ctx context.Context, scheduleA
 }
 
 func (c *Client) GetAccountSchema(ctx context.Context, sourceId string, id string) (*AccountSchema, error) {
-	schemaURL := fmt.Sprintf("%s/v2025/sources/%s/schemas/%s", c.BaseURL, sourceId, id)
+	schemaURL := c.apiURL("sources", sourceId, "schemas", id)
 	tflog.Debug(ctx, "Creating HTTP request to get account schema", map[string]interface{}{
 		"method":    "GET",
 		"url":       schemaURL,
@@ -924,7 +853,7 @@ func (c *Client) GetAccountSchema(ctx context.Context, sourceId string, id strin
 
 	res := AccountSchema{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -938,7 +867,7 @@ func (c *Client) UpdateAccountSchema(ctx context.Context, accountSchema *Account
 	if err != nil {
 		return nil, err
 	}
-	schemaURL := fmt.Sprintf("%s/v2025/sources/%s/schemas/%s", c.BaseURL, accountSchema.SourceID, accountSchema.ID)
+	schemaURL := c.apiURL("sources", accountSchema.SourceID, "schemas", accountSchema.ID)
 	tflog.Debug(ctx, "Creating HTTP request to update account schema", map[string]interface{}{
 		"method":    "PUT",
 		"url":       schemaURL,
@@ -957,7 +886,7 @@ func (c *Client) UpdateAccountSchema(ctx context.Context, accountSchema *Account
 	req = req.WithContext(ctx)
 	res := AccountSchema{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -966,9 +895,7 @@ func (c *Client) UpdateAccountSchema(ctx context.Context, accountSchema *Account
 }
 
 func (c *Client) DeleteAccountSchema(ctx context.Context, accountSchema *AccountSchema) error {
-	endpoint := fmt.Sprintf("%s/v2025/sources/%s/schemas/%s", c.BaseURL, accountSchema.SourceID, accountSchema.ID)
-
-	client := &http.Client{}
+	endpoint := c.apiURL("sources", accountSchema.SourceID, "schemas", accountSchema.ID)
 
 	tflog.Debug(ctx, "Creating HTTP request to delete account schema", map[string]interface{}{
 		"method":    "DELETE",
@@ -982,14 +909,13 @@ func (c *Client) DeleteAccountSchema(ctx context.Context, accountSchema *Account
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 
 	req = req.WithContext(ctx)
-	res, err := client.Do(req)
 
-	if err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+	var res interface{}
+	if err := c.sendRequest(ctx, req, &res); err != nil {
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -1002,7 +928,7 @@ func (c *Client) CreatePasswordPolicy(ctx context.Context, passwordPolicy *Passw
 	if err != nil {
 		return nil, err
 	}
-	policyURL := fmt.Sprintf("%s/v2025/password-policies", c.BaseURL)
+	policyURL := c.apiURL("password-policies")
 	tflog.Debug(ctx, "Creating HTTP request to create password policy", map[string]interface{}{
 		"method": "POST",
 		"url":    policyURL,
@@ -1020,7 +946,7 @@ func (c *Client) CreatePasswordPolicy(ctx context.Context, passwordPolicy *Passw
 
 	res := PasswordPolicy{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1034,7 +960,7 @@ func (c *Client) UpdatePasswordPolicy(ctx context.Context, passwordPolicy *Passw
 	if err != nil {
 		return nil, err
 	}
-	policyURL := fmt.Sprintf("%s/v2025/password-policies", c.BaseURL)
+	policyURL := c.apiURL("password-policies")
 	tflog.Debug(ctx, "Creating HTTP request to update password policy", map[string]interface{}{
 		"method": "PUT",
 		"url":    policyURL,
@@ -1051,7 +977,7 @@ func (c *Client) UpdatePasswordPolicy(ctx context.Context, passwordPolicy *Passw
 
 	res := PasswordPolicy{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1060,7 +986,7 @@ func (c *Client) UpdatePasswordPolicy(ctx context.Context, passwordPolicy *Passw
 }
 
 func (c *Client) GetPasswordPolicy(ctx context.Context, passwordPolicyId string) (*PasswordPolicy, error) {
-	policyURL := fmt.Sprintf("%s/v2025/password-policies/%s", c.BaseURL, passwordPolicyId)
+	policyURL := c.apiURL("password-policies", passwordPolicyId)
 	tflog.Debug(ctx, "Creating HTTP request to get password policy", map[string]interface{}{
 		"method":    "GET",
 		"url":       policyURL,
@@ -1076,7 +1002,7 @@ func (c *Client) GetPasswordPolicy(ctx context.Context, passwordPolicyId string)
 
 	res := PasswordPolicy{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1085,7 +1011,7 @@ func (c *Client) GetPasswordPolicy(ctx context.Context, passwordPolicyId string)
 }
 
 func (c *Client) DeletePasswordPolicy(ctx context.Context, passwordPolicyId string) error {
-	endpoint := fmt.Sprintf("%s/v2025/password-policies/%s", c.BaseURL, passwordPolicyId)
+	endpoint := c.apiURL("password-policies", passwordPolicyId)
 
 	tflog.Debug(ctx, "Creating HTTP request to delete password policy", map[string]interface{}{
 		"method":    "DELETE",
@@ -1102,29 +1028,12 @@ func (c *Client) DeletePasswordPolicy(ctx context.Context, passwordPolicyId stri
 
 	req = req.WithContext(ctx)
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		tflog.Error(ctx, "HTTP client operation failed", map[string]interface{}{"error": err.Error()})
+	var res interface{}
+	if err := c.sendRequest(ctx, req, &res); err != nil {
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return err
 	}
 
-	defer res.Body.Close()
-
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		var errRes errorResponse
-		err = json.NewDecoder(res.Body).Decode(&errRes)
-		if err == nil {
-			if res.StatusCode == http.StatusNotFound {
-				return &NotFoundError{errRes.Messages[0].Text}
-			} else {
-				return errors.New(errRes.Messages[0].Text)
-			}
-		}
-
-		return fmt.Errorf("unknown error, status code: %d", res.StatusCode)
-	}
-
 	return nil
 }
 
@@ -1134,7 +1043,7 @@ func (c *Client) CreateGovernanceGroup(ctx context.Context, governanceGroup *Gov
 		return nil, err
 	}
 
-	workgroupURL := fmt.Sprintf("%s/v2025/workgroups", c.BaseURL)
+	workgroupURL := c.apiURL("workgroups")
 	tflog.Debug(ctx, "Creating HTTP request to create governance group", map[string]interface{}{
 		"method": "POST",
 		"url":    workgroupURL,
@@ -1147,13 +1056,12 @@ func (c *Client) CreateGovernanceGroup(ctx context.Context, governanceGroup *Gov
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("X-SailPoint-Experimental", "true")
 
 	req = req.WithContext(ctx)
 
 	res := GovernanceGroup{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1162,102 +1070,53 @@ func (c *Client) CreateGovernanceGroup(ctx context.Context, governanceGroup *Gov
 }
 
 func (c *Client) GetGovernanceGroupByName(ctx context.Context, name string) ([]*GovernanceGroup, error) {
-	filter := fmt.Sprintf("name eq \"%s\"", name)
-	workgroupURL := fmt.Sprintf("%s/v2025/workgroups?filters=%s", c.BaseURL, url.QueryEscape(filter))
-	tflog.Debug(ctx, "Creating HTTP request to get governance group by name", map[string]interface{}{
-		"method": "GET",
-		"url":    workgroupURL,
-		"name":   name,
+	filter := "name eq " + FilterString(name)
+	workgroupURL := fmt.Sprintf("%s?filters=%s", c.apiURL("workgroups"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing governance groups by name", map[string]interface{}{
+		"url":  workgroupURL,
+		"name": name,
 	})
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", workgroupURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
 
-		req.Header.Set("Accept", "application/json; charset=utf-8")
-		req.Header.Set("X-SailPoint-Experimental", "true")
+	res, err := listAll(ctx, c, workgroupURL, listOptions[*GovernanceGroup]{})
+	if err != nil {
+		return nil, err
+	}
+	tflog.Debug(ctx, "GetGovernanceGroup response details", map[string]interface{}{"response": c.redactor.loggable(res)})
 
-		req = req.WithContext(ctx)
+	return res, nil
+}
 
-		res := []*GovernanceGroup{}
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
-		tflog.Debug(ctx, "GetGovernanceGroup response details", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+// ListGovernanceGroups returns every governance group matching filters, or all governance groups when filters is empty
+func (c *Client) ListGovernanceGroups(ctx context.Context, filters string) ([]*GovernanceGroup, error) {
+	workgroupURL := withFilters(c.apiURL("workgroups"), filters)
+	tflog.Debug(ctx, "Listing governance groups", map[string]interface{}{
+		"url": workgroupURL,
+	})
 
-		return res, nil
-	}
-	return nil, errors.New("dead code")
+	return listAll(ctx, c, workgroupURL, listOptions[*GovernanceGroup]{})
 }
 
 func (c *Client) GetGovernanceGroups(ctx context.Context, id string) (*GovernanceGroup, error) {
-	limit := 250
-	offset := 0
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-
-	for {
-		filter := fmt.Sprintf("id eq \"%s\"", id)
-		workgroupURL := fmt.Sprintf("%s/v2025/workgroups?filters=%s&limit=%d&offset=%d", c.BaseURL, url.QueryEscape(filter), limit, offset)
-		tflog.Debug(ctx, "Creating HTTP request to get governance groups", map[string]interface{}{
-			"method":   "GET",
-			"url":      workgroupURL,
-			"group_id": id,
-			"limit":    limit,
-			"offset":   offset,
-		})
-
-		pageResult := []*GovernanceGroup{}
-		for attempt := 1; attempt <= maxRetries; attempt++ {
-			req, err := http.NewRequest("GET", workgroupURL, nil)
-			if err != nil {
-				tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-				return nil, err
-			}
-
-			req.Header.Set("Accept", "application/json; charset=utf-8")
-			req.Header.Set("X-SailPoint-Experimental", "true")
-
-			req = req.WithContext(ctx)
-
-			if err := c.sendRequest(ctx, req, &pageResult); err != nil {
-				tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", pageResult)})
-				if (attempt < maxRetries) &&
-					(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-					backoffDelay := time.Duration(attempt) * retryDelay
-					time.Sleep(backoffDelay)
-					continue
-				}
-				return nil, err
-			}
-
-			break
-		}
-
-		tflog.Debug(ctx, "GetGovernanceGroups response details", map[string]interface{}{"response": fmt.Sprintf("%+v", pageResult)})
-		if len(pageResult) > 0 {
-			return pageResult[0], nil
-		}
+	filter := "id eq " + FilterString(id)
+	workgroupURL := fmt.Sprintf("%s?filters=%s", c.apiURL("workgroups"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing governance groups by id", map[string]interface{}{
+		"url":      workgroupURL,
+		"group_id": id,
+	})
 
-		if len(pageResult) < limit {
-			break
-		}
+	res, err := listAll(ctx, c, workgroupURL, listOptions[*GovernanceGroup]{
+		stop: func(page []*GovernanceGroup) bool { return len(page) > 0 },
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		offset += limit
+	tflog.Debug(ctx, "GetGovernanceGroups response details", map[string]interface{}{"response": c.redactor.loggable(res)})
+	if len(res) > 0 {
+		return res[0], nil
 	}
 
-	return nil, &NotFoundError{"status not found"}
+	return nil, &NotFoundError{message: "status not found"}
 }
 
 func (c *Client) UpdateGovernanceGroup(ctx context.Context, governanceGroup []*UpdateGovernanceGroup, id interface{}) (*GovernanceGroup, error) {
@@ -1265,7 +1124,7 @@ func (c *Client) UpdateGovernanceGroup(ctx context.Context, governanceGroup []*U
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/workgroups/%s", c.BaseURL, id)
+	updateURL := c.apiURL("workgroups", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to update governance group", map[string]interface{}{
 		"method":   "PATCH",
 		"url":      updateURL,
@@ -1279,13 +1138,12 @@ func (c *Client) UpdateGovernanceGroup(ctx context.Context, governanceGroup []*U
 
 	req.Header.Set("Content-Type", "application/json-patch+json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("X-SailPoint-Experimental", "true")
 
 	req = req.WithContext(ctx)
 
 	res := GovernanceGroup{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1294,7 +1152,7 @@ func (c *Client) UpdateGovernanceGroup(ctx context.Context, governanceGroup []*U
 }
 
 func (c *Client) DeleteGovernanceGroup(ctx context.Context, governanceGroup *GovernanceGroup) error {
-	deleteURL := fmt.Sprintf("%s/v2025/workgroups/%s", c.BaseURL, governanceGroup.ID)
+	deleteURL := c.apiURL("workgroups", governanceGroup.ID)
 	tflog.Debug(ctx, "Creating HTTP request to delete governance group", map[string]interface{}{
 		"method":   "DELETE",
 		"url":      deleteURL,
@@ -1307,13 +1165,12 @@ func (c *Client) DeleteGovernanceGroup(ctx context.Context, governanceGroup *Gov
 	}
 
 	req.Header.Set("Accept", "application/json; charset=utf-8")
-	req.Header.Set("X-SailPoint-Experimental", "true")
 
 	req = req.WithContext(ctx)
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -1322,73 +1179,37 @@ func (c *Client) DeleteGovernanceGroup(ctx context.Context, governanceGroup *Gov
 }
 
 func (c *Client) GetSourceAppsAll(ctx context.Context) ([]*SourceApp, error) {
-	var allApps []*SourceApp
-	offset := 0
-	limit := 250
-	for {
-		sourceAppURL := fmt.Sprintf("%s/v2025/source-apps/all?limit=%d&offset=%d", c.BaseURL, limit, offset)
-		tflog.Debug(ctx, "Creating HTTP request to get source apps", map[string]interface{}{
-			"method": "GET",
-			"url":    sourceAppURL,
-		})
-		req, err := http.NewRequest("GET", sourceAppURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
-
-		req.Header.Set("X-SailPoint-Experimental", "true")
-
-		req = req.WithContext(ctx)
-
-		var res []*SourceApp
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			return nil, err
-		}
-
-		allApps = append(allApps, res...)
-
-		if len(res) < limit-1 {
-			break
-		}
-
-		offset += limit
-	}
+	sourceAppURL := c.apiURL("source-apps", "all")
+	tflog.Debug(ctx, "Listing source apps", map[string]interface{}{
+		"url": sourceAppURL,
+	})
 
-	return allApps, nil
+	return listAll(ctx, c, sourceAppURL, listOptions[*SourceApp]{})
 }
 
 func (c *Client) GetSourceAppByName(ctx context.Context, name string) ([]*SourceApp, error) {
-	filter := fmt.Sprintf("name eq \"%s\"", name)
-	sourceAppURL := fmt.Sprintf("%s/v2025/source-apps/all?filters=%s", c.BaseURL, url.QueryEscape(filter))
-	tflog.Debug(ctx, "Creating HTTP request to get source app by name", map[string]interface{}{
-		"method":   "GET",
+	filter := "name eq " + FilterString(name)
+	sourceAppURL := fmt.Sprintf("%s?filters=%s", c.apiURL("source-apps", "all"), url.QueryEscape(filter))
+	tflog.Debug(ctx, "Listing source apps by name", map[string]interface{}{
 		"url":      sourceAppURL,
 		"app_name": name,
 	})
-	req, err := http.NewRequest("GET", sourceAppURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-		return nil, err
-	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
-
-	req = req.WithContext(ctx)
+	return listAll(ctx, c, sourceAppURL, listOptions[*SourceApp]{})
+}
 
-	var res []*SourceApp
-	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-		// Error already logged above
-		return nil, err
-	}
+// ListSourceApps returns every source app matching filters, or all source apps when filters is empty
+func (c *Client) ListSourceApps(ctx context.Context, filters string) ([]*SourceApp, error) {
+	sourceAppURL := withFilters(c.apiURL("source-apps", "all"), filters)
+	tflog.Debug(ctx, "Listing source apps", map[string]interface{}{
+		"url": sourceAppURL,
+	})
 
-	return res, nil
+	return listAll(ctx, c, sourceAppURL, listOptions[*SourceApp]{})
 }
 
 func (c *Client) GetSourceApp(ctx context.Context, id string) (*SourceApp, error) {
-	sourceAppURL := fmt.Sprintf("%s/v2025/source-apps/%s", c.BaseURL, id)
+	sourceAppURL := c.apiURL("source-apps", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to get source app", map[string]interface{}{
 		"method": "GET",
 		"url":    sourceAppURL,
@@ -1400,13 +1221,11 @@ func (c *Client) GetSourceApp(ctx context.Context, id string) (*SourceApp, error
 		return nil, err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
-
 	req = req.WithContext(ctx)
 
 	res := SourceApp{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1420,7 +1239,7 @@ func (c *Client) CreateSourceApp(ctx context.Context, sourceApp *SourceApp) (*So
 		return nil, err
 	}
 
-	sourceAppURL := fmt.Sprintf("%s/v2025/source-apps", c.BaseURL)
+	sourceAppURL := c.apiURL("source-apps")
 	tflog.Debug(ctx, "Creating HTTP request to create source app", map[string]interface{}{
 		"method": "POST",
 		"url":    sourceAppURL,
@@ -1431,7 +1250,6 @@ func (c *Client) CreateSourceApp(ctx context.Context, sourceApp *SourceApp) (*So
 		return nil, err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 
@@ -1439,7 +1257,7 @@ func (c *Client) CreateSourceApp(ctx context.Context, sourceApp *SourceApp) (*So
 
 	res := SourceApp{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1452,7 +1270,7 @@ func (c *Client) UpdateSourceApp(ctx context.Context, sourceApp []*UpdateSourceA
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/source-apps/%s", c.BaseURL, id)
+	updateURL := c.apiURL("source-apps", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to update source app", map[string]interface{}{
 		"method": "PATCH",
 		"url":    updateURL,
@@ -1464,7 +1282,6 @@ func (c *Client) UpdateSourceApp(ctx context.Context, sourceApp []*UpdateSourceA
 		return nil, err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Content-Type", "application/json-patch+json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 
@@ -1472,7 +1289,7 @@ func (c *Client) UpdateSourceApp(ctx context.Context, sourceApp []*UpdateSourceA
 
 	res := SourceApp{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1481,7 +1298,7 @@ func (c *Client) UpdateSourceApp(ctx context.Context, sourceApp []*UpdateSourceA
 }
 
 func (c *Client) DeleteSourceApp(ctx context.Context, sourceApp *SourceApp) error {
-	deleteURL := fmt.Sprintf("%s/v2025/source-apps/%s", c.BaseURL, sourceApp.ID)
+	deleteURL := c.apiURL("source-apps", sourceApp.ID)
 	tflog.Debug(ctx, "Creating HTTP request to delete source app", map[string]interface{}{
 		"method": "DELETE",
 		"url":    deleteURL,
@@ -1493,14 +1310,13 @@ func (c *Client) DeleteSourceApp(ctx context.Context, sourceApp *SourceApp) erro
 		return err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 
 	req = req.WithContext(ctx)
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -1509,37 +1325,20 @@ func (c *Client) DeleteSourceApp(ctx context.Context, sourceApp *SourceApp) erro
 }
 
 func (c *Client) GetAccessProfileAttachment(ctx context.Context, id string) (*AccessProfileAttachment, error) {
-	var accessProfiles []string
-	offset := 0
-	limit := 250
-	for {
-		url := fmt.Sprintf("%s/v2025/source-apps/%s/access-profiles?limit=%d&offset=%d", c.BaseURL, id, limit, offset)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
-
-		req.Header.Set("X-SailPoint-Experimental", "true")
-
-		req = req.WithContext(ctx)
-
-		var res []AccessProfileFromSourceApp
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			// Error already logged above
-			return nil, err
-		}
-
-		for _, ap := range res {
-			accessProfiles = append(accessProfiles, ap.ID)
-		}
+	attachmentURL := c.apiURL("source-apps", id, "access-profiles")
+	tflog.Debug(ctx, "Listing source app access profiles", map[string]interface{}{
+		"url":    attachmentURL,
+		"app_id": id,
+	})
 
-		if len(res) < limit-1 {
-			break
-		}
+	res, err := listAll(ctx, c, attachmentURL, listOptions[AccessProfileFromSourceApp]{})
+	if err != nil {
+		return nil, err
+	}
 
-		offset += limit
+	var accessProfiles []string
+	for _, ap := range res {
+		accessProfiles = append(accessProfiles, ap.ID)
 	}
 
 	accessProfileAttachment := AccessProfileAttachment{
@@ -1569,7 +1368,7 @@ func (c *Client) UpdateAccessProfileAttachment(ctx context.Context, accessProfil
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/source-apps/%s", c.BaseURL, id)
+	updateURL := c.apiURL("source-apps", fmt.Sprint(id))
 	tflog.Debug(ctx, "Creating HTTP request to update access profile attachment", map[string]interface{}{
 		"method": "PATCH",
 		"url":    updateURL,
@@ -1581,7 +1380,6 @@ func (c *Client) UpdateAccessProfileAttachment(ctx context.Context, accessProfil
 		return nil, err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Content-Type", "application/json-patch+json; charset=utf-8")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 
@@ -1589,7 +1387,7 @@ func (c *Client) UpdateAccessProfileAttachment(ctx context.Context, accessProfil
 
 	res := AccessProfileAttachment{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1603,7 +1401,7 @@ func (c *Client) DeleteAccessProfileAttachment(ctx context.Context, accessProfil
 		return err
 	}
 
-	deleteURL := fmt.Sprintf("%s/v2025/source-apps/%s/access-profiles/bulk-remove", c.BaseURL, accessProfileAttachment.SourceAppId)
+	deleteURL := c.apiURL("source-apps", accessProfileAttachment.SourceAppId, "access-profiles", "bulk-remove")
 	tflog.Debug(ctx, "Creating HTTP request to delete access profile attachment", map[string]interface{}{
 		"method":        "POST",
 		"url":           deleteURL,
@@ -1615,7 +1413,6 @@ func (c *Client) DeleteAccessProfileAttachment(ctx context.Context, accessProfil
 		return err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
@@ -1623,7 +1420,7 @@ func (c *Client) DeleteAccessProfileAttachment(ctx context.Context, accessProfil
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -1636,7 +1433,7 @@ func (c *Client) CreateGovernanceGroupMembers(ctx context.Context, governanceGro
 	if err != nil {
 		return nil, err
 	}
-	createURL := fmt.Sprintf("%s/v2025/workgroups/%s/members/bulk-add", c.BaseURL, id)
+	createURL := c.apiURL("workgroups", id, "members", "bulk-add")
 	tflog.Debug(ctx, "Creating HTTP request to create governance group members", map[string]interface{}{
 		"method":              "POST",
 		"url":                 createURL,
@@ -1648,7 +1445,6 @@ func (c *Client) CreateGovernanceGroupMembers(ctx context.Context, governanceGro
 		return nil, err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
@@ -1656,7 +1452,7 @@ func (c *Client) CreateGovernanceGroupMembers(ctx context.Context, governanceGro
 
 	res := []GovernanceGroupMembersResponse{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return nil, err
 	}
@@ -1670,54 +1466,30 @@ func (c *Client) CreateGovernanceGroupMembers(ctx context.Context, governanceGro
 	}
 
 	if !allSuccessful {
-		tflog.Error(ctx, "Creating governance group members failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Creating governance group members failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, fmt.Errorf("not all governance group members were added successfully")
 	}
 	return governanceGroupMembers, nil
 }
 
 func (c *Client) GetGovernanceGroupMembers(ctx context.Context, id string) (*GovernanceGroupMembers, error) {
-	governanceGroupMembersMembers := []*GovernanceGroupMembersMembers{}
-	offset := 0
-	limit := 50
-	for {
-		url := fmt.Sprintf("%s/v2025/workgroups/%s/members?limit=%d&offset=%d", c.BaseURL, id, limit, offset)
-		tflog.Debug(ctx, "Creating HTTP request to get governance group members", map[string]interface{}{
-			"method":              "GET",
-			"url":                 url,
-			"governance_group_id": id,
-		})
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
-
-		req.Header.Set("X-SailPoint-Experimental", "true")
-
-		req = req.WithContext(ctx)
-
-		res := []GovernanceGroupMembersMembers{}
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			// Error already logged above
-			return nil, err
-		}
-
-		for _, govgmem := range res {
-			governanceGroupMembersMembers = append(governanceGroupMembersMembers, &govgmem)
-		}
-
-		if len(res) < limit-1 {
-			break
-		}
+	membersURL := c.apiURL("workgroups", id, "members")
+	tflog.Debug(ctx, "Listing governance group members", map[string]interface{}{
+		"url":                 membersURL,
+		"governance_group_id": id,
+	})
 
-		offset += limit
+	// The members endpoint accepts at most 50 items per page
+	res, err := listAll(ctx, c, membersURL, listOptions[*GovernanceGroupMembersMembers]{
+		pageSize: 50,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	governanceGroupMembers := GovernanceGroupMembers{
 		GovernanceGroupId:             id,
-		GovernanceGroupMembersMembers: governanceGroupMembersMembers,
+		GovernanceGroupMembersMembers: res,
 	}
 
 	return &governanceGroupMembers, nil
@@ -1766,7 +1538,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 			return nil, err
 		}
 
-		deleteURL := fmt.Sprintf("%s/v2025/workgroups/%s/members/bulk-delete", c.BaseURL, governanceGroupMembers.GovernanceGroupId)
+		deleteURL := c.apiURL("workgroups", governanceGroupMembers.GovernanceGroupId, "members", "bulk-delete")
 		tflog.Debug(ctx, "Creating HTTP request to update governance group members", map[string]interface{}{
 			"method":              "POST",
 			"url":                 deleteURL,
@@ -1778,7 +1550,6 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 			return nil, err
 		}
 
-		req.Header.Set("X-SailPoint-Experimental", "true")
 		req.Header.Set("Accept", "application/json; charset=utf-8")
 		req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
@@ -1786,7 +1557,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 
 		res := []GovernanceGroupMembersResponse{}
 		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 			// Error already logged above
 			return nil, err
 		}
@@ -1800,7 +1571,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 		}
 
 		if !allSuccessful {
-			tflog.Error(ctx, "Updating governance group members during remove phase failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+			tflog.Error(ctx, "Updating governance group members during remove phase failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 			return nil, fmt.Errorf("not all governance group members were removed successfully")
 		}
 	}
@@ -1816,7 +1587,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 		if err != nil {
 			return nil, err
 		}
-		createURL := fmt.Sprintf("%s/v2025/workgroups/%s/members/bulk-add", c.BaseURL, id)
+		createURL := c.apiURL("workgroups", id, "members", "bulk-add")
 		tflog.Debug(ctx, "Creating HTTP request to update governance group members", map[string]interface{}{
 			"method":              "POST",
 			"url":                 createURL,
@@ -1828,7 +1599,6 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 			return nil, err
 		}
 
-		req.Header.Set("X-SailPoint-Experimental", "true")
 		req.Header.Set("Accept", "application/json; charset=utf-8")
 		req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
@@ -1836,7 +1606,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 
 		res := []GovernanceGroupMembersResponse{}
 		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 			// Error already logged above
 			return nil, err
 		}
@@ -1850,7 +1620,7 @@ func (c *Client) UpdateGovernanceGroupMembers(ctx context.Context, governanceGro
 		}
 
 		if !allSuccessful {
-			tflog.Error(ctx, "Updating governance group members during add phase failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+			tflog.Error(ctx, "Updating governance group members during add phase failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 			return nil, fmt.Errorf("not all governance group members were added successfully")
 		}
 	}
@@ -1864,7 +1634,7 @@ func (c *Client) DeleteGovernanceGroupMembers(ctx context.Context, governanceGro
 		return err
 	}
 
-	deleteURL := fmt.Sprintf("%s/v2025/workgroups/%s/members/bulk-delete", c.BaseURL, governanceGroupMembers.GovernanceGroupId)
+	deleteURL := c.apiURL("workgroups", governanceGroupMembers.GovernanceGroupId, "members", "bulk-delete")
 	tflog.Debug(ctx, "Creating HTTP request to delete governance group members", map[string]interface{}{
 		"method":              "POST",
 		"url":                 deleteURL,
@@ -1876,7 +1646,6 @@ func (c *Client) DeleteGovernanceGroupMembers(ctx context.Context, governanceGro
 		return err
 	}
 
-	req.Header.Set("X-SailPoint-Experimental", "true")
 	req.Header.Set("Accept", "application/json; charset=utf-8")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
@@ -1884,7 +1653,7 @@ func (c *Client) DeleteGovernanceGroupMembers(ctx context.Context, governanceGro
 
 	res := []GovernanceGroupMembersResponse{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		// Error already logged above
 		return err
 	}
@@ -1898,7 +1667,7 @@ func (c *Client) DeleteGovernanceGroupMembers(ctx context.Context, governanceGro
 	}
 
 	if !allSuccessful {
-		tflog.Error(ctx, "Deleting governance group members during failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Deleting governance group members during failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return fmt.Errorf("not all governance group members were removed successfully")
 	}
 
@@ -1906,41 +1675,29 @@ func (c *Client) DeleteGovernanceGroupMembers(ctx context.Context, governanceGro
 }
 
 func (c *Client) GetTaggedObject(ctx context.Context, objectType string, objectID string) (*TaggedObject, error) {
-	taggedObjectURL := fmt.Sprintf("%s/v2025/tagged-objects/%s/%s", c.BaseURL, url.PathEscape(objectType), url.PathEscape(objectID))
+	taggedObjectURL := c.apiURL("tagged-objects", objectType, objectID)
 	tflog.Debug(ctx, "Creating HTTP request to get tagged object", map[string]interface{}{
 		"method":      "GET",
 		"url":         taggedObjectURL,
 		"object_type": objectType,
 		"object_id":   objectID,
 	})
+	req, err := http.NewRequest("GET", taggedObjectURL, nil)
+	if err != nil {
+		tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
+		return nil, err
+	}
 
-	maxRetries := 3
-	retryDelay := 3 * time.Second
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", taggedObjectURL, nil)
-		if err != nil {
-			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
-			return nil, err
-		}
-
-		req.Header.Set("Accept", "application/json; charset=utf-8")
-		req = req.WithContext(ctx)
-
-		res := TaggedObject{}
-		if err := c.sendRequest(ctx, req, &res); err != nil {
-			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-			if (attempt < maxRetries) &&
-				(err.Error() == "rate limit exceeded (429)" || err.Error() == "Gateway Timeout error (504)") {
-				backoffDelay := time.Duration(attempt) * retryDelay
-				time.Sleep(backoffDelay)
-				continue
-			}
-			return nil, err
-		}
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req = req.WithContext(ctx)
 
-		return &res, nil
+	res := TaggedObject{}
+	if err := c.sendRequest(ctx, req, &res); err != nil {
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
+		return nil, err
 	}
-	return nil, errors.New("dead code")
+
+	return &res, nil
 }
 
 func (c *Client) SetTaggedObject(ctx context.Context, taggedObject *TaggedObject) (*TaggedObject, error) {
@@ -1949,7 +1706,7 @@ func (c *Client) SetTaggedObject(ctx context.Context, taggedObject *TaggedObject
 		return nil, err
 	}
 
-	taggedObjectURL := fmt.Sprintf("%s/v2025/tagged-objects/%s/%s", c.BaseURL, url.PathEscape(taggedObject.ObjectRef.Type), url.PathEscape(taggedObject.ObjectRef.ID))
+	taggedObjectURL := c.apiURL("tagged-objects", taggedObject.ObjectRef.Type, taggedObject.ObjectRef.ID)
 	tflog.Debug(ctx, "Creating HTTP request to set tagged object", map[string]interface{}{
 		"method":      "PUT",
 		"url":         taggedObjectURL,
@@ -1968,7 +1725,7 @@ func (c *Client) SetTaggedObject(ctx context.Context, taggedObject *TaggedObject
 
 	res := TaggedObject{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -1976,7 +1733,7 @@ func (c *Client) SetTaggedObject(ctx context.Context, taggedObject *TaggedObject
 }
 
 func (c *Client) DeleteTaggedObject(ctx context.Context, objectType string, objectID string) error {
-	taggedObjectURL := fmt.Sprintf("%s/v2025/tagged-objects/%s/%s", c.BaseURL, url.PathEscape(objectType), url.PathEscape(objectID))
+	taggedObjectURL := c.apiURL("tagged-objects", objectType, objectID)
 	tflog.Debug(ctx, "Creating HTTP request to delete tagged object", map[string]interface{}{
 		"method":      "DELETE",
 		"url":         taggedObjectURL,
@@ -1994,7 +1751,7 @@ func (c *Client) DeleteTaggedObject(ctx context.Context, objectType string, obje
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return err
 	}
 
@@ -2002,7 +1759,7 @@ func (c *Client) DeleteTaggedObject(ctx context.Context, objectType string, obje
 }
 
 func (c *Client) GetDimension(ctx context.Context, roleId string, dimensionId string) (*Dimension, error) {
-	dimensionURL := fmt.Sprintf("%s/v2025/roles/%s/dimensions/%s", c.BaseURL, roleId, dimensionId)
+	dimensionURL := c.apiURL("roles", roleId, "dimensions", dimensionId)
 	tflog.Debug(ctx, "Creating HTTP request to get dimension", map[string]interface{}{
 		"method":       "GET",
 		"url":          dimensionURL,
@@ -2020,7 +1777,7 @@ func (c *Client) GetDimension(ctx context.Context, roleId string, dimensionId st
 
 	res := Dimension{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2033,7 +1790,7 @@ func (c *Client) CreateDimension(ctx context.Context, roleId string, dimension *
 		return nil, err
 	}
 
-	createURL := fmt.Sprintf("%s/v2025/roles/%s/dimensions", c.BaseURL, roleId)
+	createURL := c.apiURL("roles", roleId, "dimensions")
 	tflog.Debug(ctx, "Creating HTTP request to create dimension", map[string]interface{}{
 		"method":  "POST",
 		"url":     createURL,
@@ -2051,7 +1808,7 @@ func (c *Client) CreateDimension(ctx context.Context, roleId string, dimension *
 
 	res := Dimension{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2063,7 +1820,7 @@ func (c *Client) UpdateDimension(ctx context.Context, roleId string, dimensionId
 	if err != nil {
 		return nil, err
 	}
-	updateURL := fmt.Sprintf("%s/v2025/roles/%s/dimensions/%s", c.BaseURL, roleId, dimensionId)
+	updateURL := c.apiURL("roles", roleId, "dimensions", dimensionId)
 	tflog.Debug(ctx, "Creating HTTP request to update dimension", map[string]interface{}{
 		"method":       "PATCH",
 		"url":          updateURL,
@@ -2082,7 +1839,7 @@ func (c *Client) UpdateDimension(ctx context.Context, roleId string, dimensionId
 
 	res := Dimension{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2090,7 +1847,7 @@ func (c *Client) UpdateDimension(ctx context.Context, roleId string, dimensionId
 }
 
 func (c *Client) DeleteDimension(ctx context.Context, roleId string, dimensionId string) error {
-	deleteURL := fmt.Sprintf("%s/v2025/roles/%s/dimensions/%s", c.BaseURL, roleId, dimensionId)
+	deleteURL := c.apiURL("roles", roleId, "dimensions", dimensionId)
 	tflog.Debug(ctx, "Creating HTTP request to delete dimension", map[string]interface{}{
 		"method":       "DELETE",
 		"url":          deleteURL,
@@ -2108,7 +1865,7 @@ func (c *Client) DeleteDimension(ctx context.Context, roleId string, dimensionId
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return err
 	}
 
@@ -2116,7 +1873,7 @@ func (c *Client) DeleteDimension(ctx context.Context, roleId string, dimensionId
 }
 
 func (c *Client) GetWorkflow(ctx context.Context, id string) (*Workflow, error) {
-	workflowURL := fmt.Sprintf("%s/v2025/workflows/%s", c.BaseURL, id)
+	workflowURL := c.apiURL("workflows", id)
 	tflog.Debug(ctx, "Creating HTTP request to get workflow", map[string]interface{}{
 		"method":      "GET",
 		"url":         workflowURL,
@@ -2134,7 +1891,7 @@ func (c *Client) GetWorkflow(ctx context.Context, id string) (*Workflow, error)
 
 	res := Workflow{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2142,35 +1899,43 @@ func (c *Client) GetWorkflow(ctx context.Context, id string) (*Workflow, error)
 }
 
 func (c *Client) GetWorkflowByName(ctx context.Context, name string) (*Workflow, error) {
-	workflowURL := fmt.Sprintf("%s/v2025/workflows", c.BaseURL)
-	tflog.Debug(ctx, "Creating HTTP request to list workflows", map[string]interface{}{
-		"method": "GET",
-		"url":    workflowURL,
-		"name":   name,
+	workflowURL := c.apiURL("workflows")
+	tflog.Debug(ctx, "Listing workflows", map[string]interface{}{
+		"url":  workflowURL,
+		"name": name,
+	})
+
+	var found *Workflow
+	_, err := listAll(ctx, c, workflowURL, listOptions[*Workflow]{
+		stop: func(page []*Workflow) bool {
+			for _, w := range page {
+				if w.Name == name {
+					found = w
+					return true
+				}
+			}
+			return false
+		},
 	})
-	req, err := http.NewRequest("GET", workflowURL, nil)
 	if err != nil {
-		tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
 		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json; charset=utf-8")
-
-	req = req.WithContext(ctx)
-
-	var res []*Workflow
-	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
-		return nil, err
+	if found != nil {
+		return found, nil
 	}
 
-	for _, w := range res {
-		if w.Name == name {
-			return w, nil
-		}
-	}
+	return nil, &NotFoundError{message: fmt.Sprintf("workflow with name %q not found", name)}
+}
 
-	return nil, &NotFoundError{fmt.Sprintf("workflow with name %q not found", name)}
+// ListWorkflows returns every workflow matching filters, or all workflows when filters is empty
+func (c *Client) ListWorkflows(ctx context.Context, filters string) ([]*Workflow, error) {
+	workflowURL := withFilters(c.apiURL("workflows"), filters)
+	tflog.Debug(ctx, "Listing workflows", map[string]interface{}{
+		"url": workflowURL,
+	})
+
+	return listAll(ctx, c, workflowURL, listOptions[*Workflow]{})
 }
 
 func (c *Client) CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workflow, error) {
@@ -2179,7 +1944,7 @@ func (c *Client) CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workf
 		return nil, err
 	}
 
-	createURL := fmt.Sprintf("%s/v2025/workflows", c.BaseURL)
+	createURL := c.apiURL("workflows")
 	tflog.Debug(ctx, "Creating HTTP request to create workflow", map[string]interface{}{
 		"method": "POST",
 		"url":    createURL,
@@ -2197,7 +1962,7 @@ func (c *Client) CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workf
 
 	res := Workflow{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2210,7 +1975,7 @@ func (c *Client) UpdateWorkflow(ctx context.Context, id string, workflow *Workfl
 		return nil, err
 	}
 
-	updateURL := fmt.Sprintf("%s/v2025/workflows/%s", c.BaseURL, id)
+	updateURL := c.apiURL("workflows", id)
 	tflog.Debug(ctx, "Creating HTTP request to update workflow", map[string]interface{}{
 		"method":      "PUT",
 		"url":         updateURL,
@@ -2229,7 +1994,7 @@ func (c *Client) UpdateWorkflow(ctx context.Context, id string, workflow *Workfl
 
 	res := Workflow{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return nil, err
 	}
 
@@ -2237,7 +2002,7 @@ func (c *Client) UpdateWorkflow(ctx context.Context, id string, workflow *Workfl
 }
 
 func (c *Client) DeleteWorkflow(ctx context.Context, id string) error {
-	deleteURL := fmt.Sprintf("%s/v2025/workflows/%s", c.BaseURL, id)
+	deleteURL := c.apiURL("workflows", id)
 	tflog.Debug(ctx, "Creating HTTP request to delete workflow", map[string]interface{}{
 		"method":      "DELETE",
 		"url":         deleteURL,
@@ -2255,7 +2020,7 @@ func (c *Client) DeleteWorkflow(ctx context.Context, id string) error {
 
 	var res interface{}
 	if err := c.sendRequest(ctx, req, &res); err != nil {
-		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", res)})
+		tflog.Error(ctx, "Request failed", map[string]interface{}{"response": c.redactor.loggable(res)})
 		return err
 	}
 
@@ -2263,104 +2028,195 @@ func (c *Client) DeleteWorkflow(ctx context.Context, id string) error {
 }
 
 func (c *Client) sendRequest(ctx context.Context, req *http.Request, v interface{}) error {
-	// Apply rate limiting before making any API requests
-	tflog.Trace(ctx, "Before rate limiter", map[string]interface{}{
-		"url": req.URL.String(),
-	})
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		tflog.Debug(ctx, "Rate limiting wait failed", map[string]interface{}{
-			"error": err.Error(),
+	_, err := c.sendRequestWithHeader(ctx, req, v)
+	return err
+}
+
+// sendRequestWithHeader behaves like sendRequest and also returns the response headers
+func (c *Client) sendRequestWithHeader(ctx context.Context, req *http.Request, v interface{}) (header http.Header, err error) {
+	c.setExperimentalHeader(req)
+
+	if c.readOnly && req.Method != http.MethodGet {
+		tflog.Warn(ctx, "Refusing request in read-only mode", map[string]interface{}{
+			"method": req.Method,
+			"url":    req.URL.String(),
 		})
-		return fmt.Errorf("rate limiting failed: %w", err)
+		return nil, &ReadOnlyError{Method: req.Method, URL: req.URL.String()}
 	}
 
-	tflog.Trace(ctx, "Sending HTTP Request", map[string]interface{}{
-		"method":       req.Method,
-		"url":          req.URL.String(),
-		"headers":      req.Header,
-		"request_body": req.Body,
-	})
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	ctx, span := startRequestSpan(ctx, req)
+	var res *http.Response
+	var stats requestSpanStats
+	defer func() { endRequestSpan(span, res, stats, err) }()
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		tflog.Error(ctx, "HTTP client operation failed", map[string]interface{}{"error": err.Error()})
-		return err
+	body := requestBody(req)
+	if c.auditLog != nil && isAuditedMethod(req.Method) {
+		defer func() { c.audit(ctx, req, body, res, v, err) }()
 	}
 
-	defer res.Body.Close()
-
-	tflog.Trace(ctx, "Received HTTP Response", map[string]interface{}{
-		"status_code":      res.StatusCode,
-		"response_headers": res.Header,
-	})
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		// Apply rate limiting before making any API requests
+		tflog.Trace(ctx, "Before rate limiter", map[string]interface{}{
+			"url": req.URL.String(),
+		})
+		waitStart := time.Now()
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			tflog.Debug(ctx, "Rate limiting wait failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return nil, fmt.Errorf("rate limiting failed: %w", err)
+		}
+		if err := c.tenantLimit.Wait(ctx); err != nil {
+			tflog.Debug(ctx, "Tenant rate limiting wait failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return nil, fmt.Errorf("rate limiting failed: %w", err)
+		}
+		stats.limiterWait += time.Since(waitStart)
 
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		var errRes errorResponse
-		err = json.NewDecoder(res.Body).Decode(&errRes)
-		if err == nil {
-			if res.StatusCode == http.StatusTooManyRequests {
-				tflog.Error(ctx, "API Rate limit exceeded", map[string]interface{}{
-					"status_code": res.StatusCode,
-				})
-				return errors.New("rate limit exceeded (429)")
-			}
-			if res.StatusCode == http.StatusNotFound {
-				// on the return statement, an interface value of type error is created by the compiler and bound to the pointer to satisfy the return argument.
-				return &NotFoundError{"status not found"}
-			}
-			if len(errRes.Messages) == 0 {
-				tflog.Error(ctx, "Unknown error occurred", map[string]interface{}{
-					"status_code": res.StatusCode,
-				})
-				return errors.New("unknown error")
+		attemptReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
 			}
-			return errors.New(errRes.Messages[0].Text)
+			attemptReq.Body = body
 		}
 
-		// Handle 429 without error response body
-		if res.StatusCode == http.StatusTooManyRequests {
-			tflog.Error(ctx, "Rate limit exceeded", map[string]interface{}{
-				"status_code": res.StatusCode,
-			})
-			return errors.New("rate limit exceeded (429)")
+		tflog.Trace(ctx, "Sending HTTP Request", map[string]interface{}{
+			"method":       attemptReq.Method,
+			"url":          attemptReq.URL.String(),
+			"headers":      attemptReq.Header,
+			"request_body": c.redactor.redactBody(body, req.Header.Get("Content-Type")),
+			"attempt":      attempt + 1,
+		})
+
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			tflog.Error(ctx, "Failed to get OAuth token", map[string]interface{}{"error": err.Error()})
+			c.health.recordTokenError(err)
+			return nil, err
 		}
+		attemptReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
-		if res.StatusCode == http.StatusBadGateway {
-			tflog.Error(ctx, "Bad Gateway error", map[string]interface{}{
-				"status_code": res.StatusCode,
+		c.health.begin()
+		res, err = c.HTTPClient.Do(attemptReq)
+		if err != nil {
+			tflog.Error(ctx, "HTTP client operation failed", map[string]interface{}{"error": err.Error()})
+			c.health.end(0, err)
+			return nil, err
+		}
+		c.tenantLimit.Observe(ctx, res.StatusCode, res.Header)
+
+		// The token may have expired or been revoked mid-apply, re-authenticate once without using the retry budget
+		if res.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			c.health.cancel()
+			reauthenticated = true
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+
+			tflog.Debug(ctx, "Access token rejected, re-authenticating", map[string]interface{}{
+				"method": req.Method,
+				"url":    req.URL.String(),
 			})
-			return errors.New("Bad Gateway error (502)")
+			c.tokens.Invalidate(token)
+			attempt--
+			continue
 		}
 
-		if res.StatusCode == http.StatusGatewayTimeout {
-			tflog.Error(ctx, "Gateway Timeout error", map[string]interface{}{
-				"status_code": res.StatusCode,
-			})
-			return errors.New("Gateway Timeout error (504)")
+		c.health.end(res.StatusCode, nil)
+
+		if attempt >= c.maxRetries || !shouldRetry(req.Method, res.StatusCode) {
+			break
 		}
 
-		tflog.Error(ctx, "Unknown error occurred", map[string]interface{}{
+		stats.retries++
+		wait := retryDelay(attempt+1, res)
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		tflog.Warn(ctx, "Retrying HTTP request", map[string]interface{}{
+			"method":      req.Method,
+			"url":         req.URL.String(),
 			"status_code": res.StatusCode,
+			"attempt":     attempt + 1,
+			"max_retries": c.maxRetries,
+			"wait":        wait.String(),
 		})
-		return errors.New(fmt.Sprintf("unknown error, code: %d", res.StatusCode))
+
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	defer res.Body.Close()
+
+	tflog.Trace(ctx, "Received HTTP Response", map[string]interface{}{
+		"status_code":      res.StatusCode,
+		"response_headers": res.Header,
+	})
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		apiErr := newAPIError(req.Method, req.URL.String(), res)
+		fields := map[string]interface{}{
+			"status_code": apiErr.StatusCode,
+			"detail_code": apiErr.DetailCode,
+			"tracking_id": apiErr.TrackingID,
+			"messages":    apiErr.messageTexts(),
+		}
+		if res.StatusCode == http.StatusNotFound {
+			// Not found is expected by reads of removed objects and by polls for a deletion, callers decide if it is an error
+			tflog.Debug(ctx, "IdentityNow API object not found", fields)
+			// on the return statement, an interface value of type error is created by the compiler and bound to the pointer to satisfy the return argument.
+			return nil, &NotFoundError{message: apiErr.Error(), apiErr: apiErr}
+		}
+		tflog.Error(ctx, "IdentityNow API request failed", fields)
+		return nil, apiErr
 	}
 
 	if res.StatusCode == 204 && req.Method == "DELETE" {
 		tflog.Debug(ctx, "Resource deleted successfully")
-		return nil
+		return res.Header, nil
 	}
 
-	if err = json.NewDecoder(res.Body).Decode(&v); err != nil {
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	tflog.Trace(ctx, "Received HTTP Response Body", map[string]interface{}{
+		"response_body": c.redactor.redactBody(data, res.Header.Get("Content-Type")),
+	})
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		// Some endpoints answer mutating calls with an empty body
+		if req.Method != "GET" {
+			return res.Header, nil
+		}
+		return nil, io.EOF
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
 		tflog.Error(ctx, "JSON decoder error", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return err
+		return nil, err
 	}
+	return res.Header, nil
+}
 
-	tflog.Trace(ctx, "Parsed HTTP Response", map[string]interface{}{
-		"response_body": v,
-	})
-	return nil
+// requestBody returns a copy of the request body, so it can still be logged once the request is sent
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return data
 }
@@ -0,0 +1,941 @@
+package identitynow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/plsph/terraform-provider-identitynow/identitynow/identitynowtest"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient(context.Background(), server.URL, "client-id", "client-secret", 1000, 3)
+	client.tokens.token = "token"
+	client.tokens.expiry = time.Now().Add(time.Hour)
+	return client
+}
+
+// newFakeClient returns a client of a fake tenant that polls tasks without delay
+func newFakeClient(fake *identitynowtest.Server) *Client {
+	client := NewClient(context.Background(), fake.URL, identitynowtest.ClientID, identitynowtest.ClientSecret, 1000, 3)
+	client.taskPollMin = time.Millisecond
+	return client
+}
+
+func TestSendRequestRetriesThrottledRequests(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"id":"2c91808a7813090a017814121e121518","name":"Example"}`))
+	})
+
+	source, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.Name != "Example" {
+		t.Fatalf("unexpected source name %q", source.Name)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestSendRequestDoesNotRetryGatewayErrorsForPost(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	if _, err := client.CreateRole(context.Background(), &Role{Name: "Example"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestWaitFromHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	cases := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"retry-after seconds", http.Header{"Retry-After": []string{"7"}}, 7 * time.Second},
+		{"retry-after date", http.Header{"Retry-After": []string{now.Add(3 * time.Second).UTC().Format(http.TimeFormat)}}, 3 * time.Second},
+		{"reset relative", http.Header{"X-Ratelimit-Reset": []string{"4"}}, 4 * time.Second},
+		{"reset epoch", http.Header{"X-Ratelimit-Reset": []string{"1700000010"}}, 10 * time.Second},
+	}
+
+	for _, tc := range cases {
+		got, ok := waitFromHeaders(tc.header, now)
+		if !ok || got != tc.want {
+			t.Errorf("%s: got %s (%t), want %s", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestSendRequestReturnsAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"detailCode":"409.0 Conflict","trackingId":"b2d4c2ba1c3f4d4a","messages":[` +
+			`{"locale":"en-US","localeOrigin":"DEFAULT","text":"Name already in use"},` +
+			`{"locale":"und","localeOrigin":"REQUEST","text":"Name already in use"}]}`))
+	})
+
+	_, err := client.CreateRole(context.Background(), &Role{Name: "Example"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusConflict || apiErr.TrackingID != "b2d4c2ba1c3f4d4a" || len(apiErr.Messages) != 2 {
+		t.Fatalf("unexpected APIError %+v", apiErr)
+	}
+	if apiErr.Method != http.MethodPost || !strings.HasSuffix(apiErr.URL, "/v2025/roles") {
+		t.Fatalf("unexpected request in APIError %+v", apiErr)
+	}
+	if !strings.Contains(err.Error(), "tracking id: b2d4c2ba1c3f4d4a") {
+		t.Fatalf("tracking id missing from %q", err.Error())
+	}
+}
+
+func TestSendRequestNotFoundUnwrapsToAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.GetRole(context.Background(), "missing")
+
+	if !IsNotFound(err) {
+		t.Fatalf("expected a NotFoundError, got %T", err)
+	}
+	if !IsNotFound(fmt.Errorf("reading role: %w", err)) {
+		t.Fatal("expected a wrapped NotFoundError to be recognized")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a wrapped 404 APIError, got %v", err)
+	}
+}
+
+func TestListAllFollowsTotalCount(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("count") != "true" {
+			t.Errorf("count=true missing from %s", r.URL)
+		}
+		w.Header().Set("X-Total-Count", "5")
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+		case "2":
+			w.Write([]byte(`[{"id":"3"},{"id":"4"}]`))
+		case "4":
+			w.Write([]byte(`[{"id":"5"}]`))
+		default:
+			t.Errorf("unexpected offset in %s", r.URL)
+		}
+	})
+
+	apps, err := listAll(context.Background(), client, client.BaseURL+"/v2025/source-apps/all", listOptions[*SourceApp]{pageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(apps) != 5 || calls != 3 {
+		t.Fatalf("expected 5 items in 3 calls, got %d items in %d calls", len(apps), calls)
+	}
+}
+
+func TestSendRequestReauthenticatesOnUnauthorized(t *testing.T) {
+	var tokenCalls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			atomic.AddInt32(&tokenCalls, 1)
+			w.Write([]byte(`{"access_token":"fresh","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"1","name":"Example"}`))
+	})
+
+	if _, err := client.GetRole(context.Background(), "1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if tokenCalls != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenCalls)
+	}
+}
+
+func TestTokenSourceSharesInFlightRefresh(t *testing.T) {
+	var fetches int32
+	release := make(chan struct{})
+	ts := newTokenSource(context.Background(), func(ctx context.Context) (*OauthToken, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return &OauthToken{AccessToken: "shared", ExpiresIn: 3600}, nil
+	})
+	defer ts.Stop()
+
+	results := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			token, _ := ts.Token(context.Background())
+			results <- token
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 10; i++ {
+		if token := <-results; token != "shared" {
+			t.Fatalf("unexpected token %q", token)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected 1 token fetch, got %d", fetches)
+	}
+}
+
+func TestAdaptiveRateLimiterFollowsHeaders(t *testing.T) {
+	l := tenantRateLimiter("https://adaptive.example.com")
+	if l != tenantRateLimiter("https://ADAPTIVE.example.com/") {
+		t.Fatal("expected one limiter per tenant")
+	}
+
+	l.Observe(context.Background(), http.StatusOK, http.Header{
+		"X-Ratelimit-Limit":     []string{"100"},
+		"X-Ratelimit-Remaining": []string{"50"},
+		"X-Ratelimit-Reset":     []string{"10"},
+	})
+	if got := float64(l.limiter.Limit()); got != 5 {
+		t.Fatalf("expected a rate of 5/s, got %v", got)
+	}
+	if got := l.limiter.Burst(); got != 5 {
+		t.Fatalf("expected a burst of 5, got %d", got)
+	}
+
+	l.Observe(context.Background(), http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}})
+	if wait := time.Until(l.pausedUntil); wait < 29*time.Second {
+		t.Fatalf("expected the tenant to be paused for 30s, got %s", wait)
+	}
+}
+
+func TestCassetteRecordsAndReplays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"live-token","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"id":"2c91808a7813090a017814121e121518","name":"Example","password":"hunter2"}`))
+	}))
+
+	t.Setenv("IDENTITYNOW_CASSETTE", path)
+	t.Setenv("IDENTITYNOW_CASSETTE_MODE", CassetteModeRecord)
+	recorder, err := CassetteTransportFromEnv(http.DefaultTransport, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client := NewClient(context.Background(), server.URL, "client-id", "client-secret", 1000, 3)
+	client.HTTPClient.Transport = recorder
+	if _, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, secret := range []string{"client-secret", "live-token", "hunter2"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+
+	t.Setenv("IDENTITYNOW_CASSETTE_MODE", CassetteModeReplay)
+	player, err := CassetteTransportFromEnv(http.DefaultTransport, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client = NewClient(context.Background(), "https://replay.invalid", "client-id", "client-secret", 1000, 3)
+	client.HTTPClient.Transport = player
+	source, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.Name != "Example" {
+		t.Fatalf("unexpected source name %q", source.Name)
+	}
+	if _, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518"); err == nil {
+		t.Fatal("expected an error once the cassette is exhausted")
+	}
+}
+
+func TestCassetteRedactsPatchedConnectorSecrets(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv("IDENTITYNOW_CASSETTE", path)
+	t.Setenv("IDENTITYNOW_CASSETTE_MODE", CassetteModeRecord)
+	redactor, err := NewRedactor([]string{"msGraphTokenBase"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	recorder, err := CassetteTransportFromEnv(http.DefaultTransport, redactor)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client := newFakeClient(fake)
+	client.HTTPClient.Transport = recorder
+
+	_, err = client.CreateSource(context.Background(), &Source{
+		Name:      "Entra",
+		Connector: "Microsoft-Entra",
+		Owner:     &Owner{Type: "IDENTITY", ID: "owner"},
+		ConnectorAttributes: &ConnectorAttributes{
+			ClientSecret:     "entra-client-secret",
+			MsGraphTokenBase: "configured-secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(data), "/connectorAttributes/client_secret") {
+		t.Fatalf("expected the cassette to record the patch, got %s", data)
+	}
+	for _, secret := range []string{"entra-client-secret", "configured-secret", identitynowtest.ClientSecret} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+}
+
+func TestCassetteRedactsPasswordLikeConnectorAttributes(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	id := fake.Seed("sources", map[string]interface{}{
+		"name": "LDAP",
+		"connectorAttributes": map[string]interface{}{
+			"host":         "ldap.example.com",
+			"bindPassword": "ldap-bind-password",
+			"sharedSecret": "ldap-shared-secret",
+		},
+	})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv("IDENTITYNOW_CASSETTE", path)
+	t.Setenv("IDENTITYNOW_CASSETTE_MODE", CassetteModeRecord)
+	recorder, err := CassetteTransportFromEnv(http.DefaultTransport, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client := newFakeClient(fake)
+	client.HTTPClient.Transport = recorder
+	if _, err := client.GetSource(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(data), "ldap.example.com") {
+		t.Fatalf("expected the cassette to record the source, got %s", data)
+	}
+	for _, secret := range []string{"ldap-bind-password", "ldap-shared-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+}
+
+func TestClientSourceLifecycleAgainstFakeTenant(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	ctx := context.Background()
+
+	created, err := client.CreateSource(ctx, &Source{Name: "HR", Connector: "delimited-file", Owner: &Owner{Type: "IDENTITY", ID: "owner"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(created.Schemas) != 2 {
+		t.Fatalf("expected account and group schemas, got %+v", created.Schemas)
+	}
+	if _, err := client.CreateSource(ctx, &Source{Name: "hr", Connector: "delimited-file"}); err == nil {
+		t.Fatal("expected a duplicate name to be rejected")
+	}
+
+	found, err := client.GetSourceByName(ctx, "HR")
+	if err != nil || len(found) != 1 || found[0].ID != created.ID {
+		t.Fatalf("unexpected lookup result %+v, %v", found, err)
+	}
+
+	created.Description = "Human resources"
+	if _, err := client.UpdateSource(ctx, created); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	fake.Seed("entitlements", map[string]interface{}{"name": "Admins", "source": map[string]interface{}{"id": created.ID}})
+	fake.Seed("entitlements", map[string]interface{}{"name": "Admins", "source": map[string]interface{}{"id": "other"}})
+	entitlements, err := client.GetSourceEntitlement(ctx, created.ID, "Admins")
+	if err != nil || len(entitlements) != 1 {
+		t.Fatalf("unexpected entitlements %+v, %v", entitlements, err)
+	}
+
+	schema, err := client.GetAccountSchema(ctx, created.ID, created.Schemas[0].ID)
+	if err != nil || schema.Name != "account" {
+		t.Fatalf("unexpected schema %+v, %v", schema, err)
+	}
+
+	if err := client.DeleteSource(ctx, created); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var notFound *NotFoundError
+	if _, err := client.GetSource(ctx, created.ID); !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+	if _, err := client.GetAccountSchema(ctx, created.ID, created.Schemas[0].ID); !errors.As(err, &notFound) {
+		t.Fatalf("expected schemas to be deleted with the source, got %v", err)
+	}
+}
+
+func TestClientRolePatchAndDimensionsAgainstFakeTenant(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	ctx := context.Background()
+
+	role, err := client.CreateRole(ctx, &Role{Name: "Engineering", Description: "initial"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	patched, err := client.UpdateRole(ctx, []*UpdateRole{
+		{Op: "replace", Path: "/description", Value: "patched"},
+		{Op: "add", Path: "/accessProfiles", Value: []interface{}{map[string]interface{}{"id": "ap1", "type": "ACCESS_PROFILE", "name": "AP"}}},
+	}, role.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if patched.Description != "patched" || len(patched.AccessProfiles) != 1 {
+		t.Fatalf("patch not applied: %+v", patched)
+	}
+
+	dimension, err := client.CreateDimension(ctx, role.ID, &Dimension{Name: "Berlin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.GetDimension(ctx, role.ID, dimension.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := client.DeleteRole(ctx, role); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var notFound *NotFoundError
+	if _, err := client.GetDimension(ctx, role.ID, dimension.ID); !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+}
+
+func TestClientGovernanceGroupMembersAgainstFakeTenant(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	ctx := context.Background()
+
+	group, err := client.CreateGovernanceGroup(ctx, &GovernanceGroup{Name: "Approvers"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// More members than fit on one page of the members endpoint
+	desired := &GovernanceGroupMembers{GovernanceGroupId: group.ID}
+	for i := 0; i < 60; i++ {
+		desired.GovernanceGroupMembersMembers = append(desired.GovernanceGroupMembersMembers, &GovernanceGroupMembersMembers{ID: fmt.Sprintf("identity-%d", i), Type: "IDENTITY"})
+	}
+	if _, err := client.CreateGovernanceGroupMembers(ctx, desired, group.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	actual, err := client.GetGovernanceGroupMembers(ctx, group.ID)
+	if err != nil || len(actual.GovernanceGroupMembersMembers) != 60 {
+		t.Fatalf("unexpected members %d, %v", len(actual.GovernanceGroupMembersMembers), err)
+	}
+
+	desired.GovernanceGroupMembersMembers = desired.GovernanceGroupMembersMembers[:10]
+	if _, err := client.UpdateGovernanceGroupMembers(ctx, desired, actual, group.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	actual, err = client.GetGovernanceGroupMembers(ctx, group.ID)
+	if err != nil || len(actual.GovernanceGroupMembersMembers) != 10 {
+		t.Fatalf("unexpected members %d, %v", len(actual.GovernanceGroupMembersMembers), err)
+	}
+
+	byID, err := client.GetGovernanceGroups(ctx, group.ID)
+	if err != nil || byID.Name != "Approvers" {
+		t.Fatalf("unexpected group %+v, %v", byID, err)
+	}
+}
+
+func TestAPIURLFollowsVersionOverrides(t *testing.T) {
+	client := NewClient(context.Background(), "https://tenant.api.identitynow.com/", "client-id", "client-secret", 1000, 3)
+	client.apiVersions = apiVersions{defaultVersion: "v2026", overrides: map[string]string{"workflows": "beta"}}
+
+	for actual, expected := range map[string]string{
+		client.apiURL("roles", "a/b", "dimensions"):                "https://tenant.api.identitynow.com/v2026/roles/a%2Fb/dimensions",
+		client.apiURL("workflows", "id"):                           "https://tenant.api.identitynow.com/beta/workflows/id",
+		client.apiURL("source", "getAggregationSchedules", "src1"): "https://tenant.api.identitynow.com/cc/api/source/getAggregationSchedules/src1",
+	} {
+		if actual != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+
+	for target, experimental := range map[string]bool{
+		client.apiURL("workgroups", "id", "members"): true,
+		client.apiURL("source-apps", "all"):          true,
+		client.apiURL("sources", "id"):               false,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		client.setExperimentalHeader(req)
+		if (req.Header.Get("X-SailPoint-Experimental") == "true") != experimental {
+			t.Errorf("%s: expected experimental header %v", target, experimental)
+		}
+	}
+
+	if err := ValidateAPIVersions("v2025", map[string]string{"unknown": "beta"}); err == nil {
+		t.Error("expected an error for an unknown API resource")
+	}
+}
+
+func TestHTTPTransportTrustsConfiguredCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	untrusted, err := NewHTTPTransport(TransportSettings{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := (&http.Client{Transport: untrusted}).Get(server.URL); err == nil {
+		t.Fatal("expected the self-signed certificate to be rejected")
+	}
+
+	trusted, err := NewHTTPTransport(TransportSettings{CACertPEM: string(caPEM)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res, err := (&http.Client{Transport: trusted}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+
+	if _, err := NewHTTPTransport(TransportSettings{CACertPEM: string(caPEM), CACertFile: "ca.pem"}); err == nil {
+		t.Fatal("expected an error when both ca_cert_pem and ca_cert_file are set")
+	}
+	if _, err := NewHTTPTransport(TransportSettings{ClientCertPEM: string(caPEM)}); err == nil {
+		t.Fatal("expected an error for a client certificate without key")
+	}
+}
+
+func TestTokenFileIsReReadAfterRotation(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("first\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var current atomic.Value
+	current.Store("first")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+current.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"2c91808a7813090a017814121e121518","name":"Example"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(context.Background(), server.URL, "", "", 1000, 3)
+	client.tokenFile = tokenFile
+	if _, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A sidecar rotates the token and the old one is revoked
+	current.Store("second")
+	if err := os.WriteFile(tokenFile, []byte("second\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestJWTExpiresIn(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1700000600}`))
+	if expiresIn, ok := jwtExpiresIn("header."+payload+".signature", now); !ok || expiresIn != 600 {
+		t.Fatalf("expected 600 seconds, got %d (%v)", expiresIn, ok)
+	}
+	if _, ok := jwtExpiresIn("opaque-token", now); ok {
+		t.Fatal("expected no expiry for an opaque token")
+	}
+}
+
+func TestTokenCacheIsSharedAcrossRuns(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			atomic.AddInt32(&tokenRequests, 1)
+			w.Write([]byte(`{"access_token":"cached-token","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"id":"2c91808a7813090a017814121e121518","name":"Example"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for run := 0; run < 2; run++ {
+		// Every run is a new provider process with its own clients
+		cache, err := NewTokenCache(dir, "cache-key")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		client := NewClient(context.Background(), server.URL, "client-id", "client-secret", 1000, 3)
+		client.tokens.usePersistentCache(cache, client.BaseURL, client.clientId)
+		if _, err := client.GetSource(context.Background(), "2c91808a7813090a017814121e121518"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		client.tokens.Stop()
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenRequests)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		data, _ := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if strings.Contains(string(data), "cached-token") {
+			t.Fatal("token cache is not encrypted")
+		}
+	}
+
+	other, _ := NewTokenCache(dir, "another-key")
+	if _, _, ok := other.Load(server.URL, "client-id", time.Now()); ok {
+		t.Fatal("expected the cache to be unreadable with another key")
+	}
+}
+
+func TestSendRequestRecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("SLPT-Request-ID", "b2d4c2ba1c3f4d4a")
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"id":"2c91808a7813090a017814121e121518","name":"Example"}`))
+	})
+
+	ctx, span := tracer().Start(context.Background(), "identitynow_source.read")
+	if _, err := client.GetSource(ctx, "2c91808a7813090a017814121e121518"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	request, operation := spans[0], spans[1]
+	if operation.Name() != "identitynow_source.read" || request.Parent().SpanID() != operation.SpanContext().SpanID() {
+		t.Fatalf("request span %q is not a child of %q", request.Name(), operation.Name())
+	}
+	if request.Name() != "GET /v2025/sources/{id}" {
+		t.Fatalf("unexpected request span name %q", request.Name())
+	}
+
+	attributes := map[string]string{}
+	for _, kv := range request.Attributes() {
+		attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+	for key, want := range map[string]string{
+		"http.request.method":       "GET",
+		"url.template":              "/v2025/sources/{id}",
+		"http.response.status_code": "200",
+		"http.request.resend_count": "1",
+		"identitynow.tracking_id":   "b2d4c2ba1c3f4d4a",
+	} {
+		if attributes[key] != want {
+			t.Errorf("attribute %s = %q, want %q", key, attributes[key], want)
+		}
+	}
+	if _, ok := attributes["identitynow.rate_limiter.wait_ms"]; !ok {
+		t.Error("rate limiter wait is not recorded")
+	}
+}
+
+func TestAuditLogRecordsMutatingCalls(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	ctx := context.Background()
+
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := OpenAuditLog(auditPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client.auditLog = audit
+
+	role, err := client.CreateRole(ctx, &Role{Name: "Engineering"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.GetRole(ctx, role.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := client.UpdateRole(ctx, []*UpdateRole{{Op: "replace", Path: "/description", Value: "patched"}}, role.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client.CreateRole(ctx, &Role{Name: fmt.Sprintf("Concurrent %d", i)})
+		}(i)
+	}
+	wg.Wait()
+
+	if _, err := client.DeleteRole(ctx, role); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 13 {
+		t.Fatalf("expected 13 audit lines without the GET, got %d", len(lines))
+	}
+	entries := make([]auditEntry, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("line %d is not JSON: %s", i, err)
+		}
+	}
+
+	created, patched, deleted := entries[0], entries[1], entries[12]
+	if created.Operation != http.MethodPost || created.ResourceType != "roles" || created.ObjectID != role.ID || created.Status != http.StatusCreated {
+		t.Fatalf("unexpected create entry %+v", created)
+	}
+	if created.ClientID != identitynowtest.ClientID || created.Payload == nil {
+		t.Fatalf("create entry lacks client id or payload %+v", created)
+	}
+	if patched.Operation != http.MethodPatch || patched.ObjectID != role.ID || patched.Patch == nil {
+		t.Fatalf("unexpected patch entry %+v", patched)
+	}
+	if deleted.Operation != http.MethodDelete || deleted.ObjectID != role.ID {
+		t.Fatalf("unexpected delete entry %+v", deleted)
+	}
+}
+
+func TestRedactorRules(t *testing.T) {
+	r, err := NewRedactor([]string{"$.connectorAttributes.domainSettings.*", "apiToken"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	patch := r.redactBody([]byte(`[{"op":"replace","path":"/connectorAttributes/IQServicePassword","value":"hunter2"},`+
+		`{"op":"add","path":"/connectorAttributes","value":{"client_secret":"s3cret","domain":"example.com"}}]`), "application/json-patch+json")
+	body := r.redactBody([]byte(`{"name":"AD","connectorAttributes":{"apiToken":"t0ken","domainSettings":{"user":"svc","pwd":"p4ss"},`+
+		`"forestSettings":[{"password":"f0rest","user":"forest-admin"}]}}`), "application/json")
+
+	out, _ := json.Marshal([]interface{}{patch, body})
+	for _, secret := range []string{"hunter2", "s3cret", "t0ken", "p4ss", "svc", "f0rest"} {
+		if strings.Contains(string(out), secret) {
+			t.Fatalf("%s not redacted: %s", secret, out)
+		}
+	}
+	for _, kept := range []string{"example.com", "forest-admin", `"name":"AD"`} {
+		if !strings.Contains(string(out), kept) {
+			t.Fatalf("%s redacted: %s", kept, out)
+		}
+	}
+
+	if _, err := NewRedactor([]string{"$connectorAttributes"}); err == nil {
+		t.Fatal("expected an invalid rule to be rejected")
+	}
+}
+
+func TestReadOnlyClientRefusesChanges(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	client.readOnly = true
+	ctx := context.Background()
+
+	var readOnlyErr *ReadOnlyError
+	if _, err := client.CreateRole(ctx, &Role{Name: "Engineering"}); !errors.As(err, &readOnlyErr) {
+		t.Fatalf("expected ReadOnlyError, got %v", err)
+	}
+	if n := fake.RequestCount(http.MethodPost, "/v2025/roles"); n != 0 {
+		t.Fatalf("expected no request to be sent, got %d", n)
+	}
+	if _, err := client.GetSourceByName(ctx, "HR"); err != nil {
+		t.Fatalf("expected reads to keep working, got %v", err)
+	}
+}
+
+func TestLookupFiltersEscapeValues(t *testing.T) {
+	if got, want := eqAnyFilter("email", []string{`a"b@example.com`, `c\d@example.com`}), `email eq "a\"b@example.com" or email eq "c\\d@example.com"`; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got, want := inFilter("name", []string{`Say "hi"`}), `name in ("Say \"hi\"")`; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestConcurrentIdentityLookupsAreBatchedAndCached(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	client.lookups = newLookups(func(context.Context) (*Client, error) { return client, nil }, newReadCache(time.Minute, 10))
+	ctx := context.Background()
+
+	for _, email := range []string{"ada@example.com", "grace@example.com", "alan@example.com"} {
+		fake.Seed("identities", map[string]interface{}{"name": email, "email": email, "emailAddress": email})
+	}
+
+	emails := []string{"ada@example.com", "GRACE@example.com", "alan@example.com", "ada@example.com", "nobody@example.com"}
+	results := make([][]*Identity, len(emails))
+	errs := make([]error, len(emails))
+	var wg sync.WaitGroup
+	for i, email := range emails {
+		wg.Add(1)
+		go func(i int, email string) {
+			defer wg.Done()
+			results[i], errs[i] = client.GetIdentityByEmail(ctx, email)
+		}(i, email)
+	}
+	wg.Wait()
+
+	for i, email := range emails {
+		if errs[i] != nil {
+			t.Fatalf("unexpected error for %s: %s", email, errs[i])
+		}
+		want := 1
+		if email == "nobody@example.com" {
+			want = 0
+		}
+		if len(results[i]) != want || (want == 1 && !strings.EqualFold(results[i][0].EmailAddress, email)) {
+			t.Fatalf("unexpected identities for %s: %+v", email, results[i])
+		}
+	}
+	if n := fake.RequestCount(http.MethodGet, "/v2025/identities"); n != 1 {
+		t.Fatalf("expected one batched request, got %d", n)
+	}
+
+	if _, err := client.GetIdentityByEmail(ctx, "grace@example.com"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n := fake.RequestCount(http.MethodGet, "/v2025/identities"); n != 1 {
+		t.Fatalf("expected the lookup to be served from the cache, got %d requests", n)
+	}
+}
+
+func TestReadCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newReadCache(time.Minute, 2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a")
+	cache.Put("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be kept")
+	}
+
+	expired := newReadCache(time.Nanosecond, 2)
+	expired.Put("a", 1)
+	time.Sleep(time.Millisecond)
+	if _, ok := expired.Get("a"); ok {
+		t.Fatal("expected a to expire")
+	}
+}
+
+func TestDeleteSourceWaitsForTask(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	client := newFakeClient(fake)
+	ctx := context.Background()
+
+	source, err := client.CreateSource(ctx, &Source{Name: "HR", Connector: "delimited-file"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := client.DeleteSource(ctx, source); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fake.Object("sources", source.ID) != nil {
+		t.Fatal("expected the source to be gone once DeleteSource returns")
+	}
+
+	fake.FailTasks = true
+	other, err := client.CreateSource(ctx, &Source{Name: "Payroll", Connector: "delimited-file"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var taskErr *TaskError
+	err = client.DeleteSource(ctx, other)
+	if !errors.As(err, &taskErr) || taskErr.CompletionStatus != "ERROR" {
+		t.Fatalf("expected a TaskError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "the object is still referenced") {
+		t.Fatalf("task message missing from %q", err.Error())
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.WaitForTask(canceled, "2c91808a7813090a017814121e121518"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
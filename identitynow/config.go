@@ -0,0 +1,348 @@
+package identitynow
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// Config is the configuration parameters for an IdentityNow API
+type ClientCredential struct {
+    ClientId     string `json:"client_id"`
+    ClientSecret string `json:"client_secret"`
+}
+
+type Config struct {
+	URL                   string `json:"url"`
+	ClientId              string `json:"client_id,omitempty"`
+	ClientSecret          string `json:"client_secret,omitempty"`
+        Credentials           []ClientCredential `json:"credentials,omitempty"`
+	MaxClientPoolSize     int    `json:"max_client_pool_size,omitempty" default:"1"`
+	DefaultClientPoolSize int    `json:"default_client_pool_size,omitempty" default:"1"`
+	ClientRequestRateLimit int   `json:"client_request_rate_limit" default:"10"`
+	MaxRetries            int    `json:"max_retries" default:"5"`
+	APIVersion            string `json:"api_version" default:"v2025"`
+	APIVersionOverrides   map[string]string `json:"api_version_overrides,omitempty"`
+	HTTPTimeout           time.Duration `json:"http_timeout" default:"60s"`
+	ReadCacheTTL          time.Duration `json:"read_cache_ttl" default:"5m"`
+	ReadCacheMaxEntries   int           `json:"read_cache_max_entries" default:"1000"`
+	AccessToken           string `json:"-"`
+	TokenFile             string `json:"token_file,omitempty"`
+	AuditLogPath          string `json:"audit_log_path,omitempty"`
+	ReadOnly              bool   `json:"read_only"`
+	RedactionKeys         []string `json:"redaction_keys,omitempty"`
+
+	// Client pool for round-robin token management
+	clients        []*Client
+	clientIndex    int
+	clientPoolSize int
+	clientMux      sync.Mutex
+
+	// TokenCache persists client credentials tokens across Terraform runs when configured
+	TokenCache *TokenCache
+
+	// AuditLog receives a line per mutating API call when audit_log_path is set
+	AuditLog *AuditLog
+
+	// Redactor removes secrets from logged and audited payloads, including the redaction_keys rules
+	Redactor *Redactor
+
+	// lookups caches and batches data source lookups for every pooled client
+	lookups *lookups
+
+	// Transport is used by every pooled client, e.g. to record or replay API traffic
+	Transport http.RoundTripper
+
+	// Shared state per credential index, used by every pooled client of that credential
+	credentialStates map[int]*credentialState
+}
+
+// credentialState holds what pooled clients of the same credential share
+type credentialState struct {
+	tokens      *tokenSource
+	health      *credentialHealth
+	rateLimiter *rate.Limiter
+}
+
+func (c *Client) IsTokenValid(ctx context.Context) bool {
+	tflog.Debug(ctx, "Checking if token is valid", map[string]interface{}{
+		"token_expiry": c.tokens.Expiry(),
+		"now":          time.Now(),
+	})
+	return c.tokens.Valid()
+}
+
+// initializeClientPool ensures the client pool is properly initialized
+func (cfg *Config) initializeClientPool() {
+	if cfg.clientPoolSize == 0 {
+		cfg.clientPoolSize = cfg.DefaultClientPoolSize
+	}
+	if cfg.clientPoolSize > cfg.MaxClientPoolSize {
+		cfg.clientPoolSize = cfg.MaxClientPoolSize
+	}
+	if cfg.clients == nil {
+		cfg.clients = make([]*Client, cfg.clientPoolSize)
+	}
+}
+
+// credentialIndexFor maps a pool slot to the credential it uses
+func (cfg *Config) credentialIndexFor(clientIndex int) int {
+	return clientIndex % len(cfg.Credentials)
+}
+
+// getNextClientIndex returns the pool slot for the next request. It prefers healthy
+// credentials with the fewest requests in flight, starting from the round-robin
+// position to spread ties. Credentials in skip are not considered. When every
+// credential is ejected, the one that returns to rotation first is used.
+func (cfg *Config) getNextClientIndex(skip map[int]bool) int {
+	now := time.Now()
+	best, bestLoad := -1, 0
+	fallback := -1
+	var fallbackUntil time.Time
+
+	for i := 0; i < cfg.clientPoolSize; i++ {
+		index := (cfg.clientIndex + i) % cfg.clientPoolSize
+		credentialIndex := cfg.credentialIndexFor(index)
+		if skip[credentialIndex] {
+			continue
+		}
+
+		state, ok := cfg.credentialStates[credentialIndex]
+		if !ok {
+			// Credentials that were never used are healthy and idle
+			if best == -1 || bestLoad > 0 {
+				best, bestLoad = index, 0
+			}
+			continue
+		}
+
+		load, ejectedUntil := state.health.load()
+		if state.health.healthy(now) {
+			if best == -1 || load < bestLoad {
+				best, bestLoad = index, load
+			}
+		} else if fallback == -1 || ejectedUntil.Before(fallbackUntil) {
+			fallback, fallbackUntil = index, ejectedUntil
+		}
+	}
+
+	if best == -1 {
+		best = fallback
+	}
+	if best == -1 {
+		// Every credential was skipped, keep plain round-robin
+		best = cfg.clientIndex
+	}
+
+	cfg.clientIndex = (best + 1) % cfg.clientPoolSize
+	return best
+}
+
+// nextClient picks the next pooled client, creating it if needed.
+// It only holds clientMux for bookkeeping, never across network calls.
+func (cfg *Config) nextClient(ctx context.Context, skip map[int]bool) (*Client, int) {
+	cfg.clientMux.Lock()
+	defer cfg.clientMux.Unlock()
+
+	cfg.initializeClientPool()
+
+	clientIndex := cfg.getNextClientIndex(skip)
+
+	tflog.Debug(ctx, "Selecting client from pool", map[string]interface{}{
+		"client_index": clientIndex,
+		"pool_size":    cfg.clientPoolSize,
+	})
+
+	// Create new client if we don't have one at this index
+	if cfg.clients[clientIndex] == nil {
+		credentialIndex := cfg.credentialIndexFor(clientIndex)
+		credential := cfg.Credentials[credentialIndex]
+		tflog.Debug(ctx, "Creating new IdentityNow client in pool", map[string]interface{}{
+			"client_index": clientIndex,
+			"base_url":     cfg.URL,
+			"client_id":    credential.ClientId,
+		})
+		client := NewClient(ctx, cfg.URL, credential.ClientId, credential.ClientSecret, cfg.ClientRequestRateLimit, cfg.MaxRetries)
+		// The token request and API requests share this http.Client
+		if cfg.Transport != nil {
+			client.HTTPClient.Transport = cfg.Transport
+		}
+		if cfg.HTTPTimeout > 0 {
+			client.HTTPClient.Timeout = cfg.HTTPTimeout
+		}
+		client.accessToken = cfg.AccessToken
+		client.tokenFile = cfg.TokenFile
+		client.auditLog = cfg.AuditLog
+		client.readOnly = cfg.ReadOnly
+		if cfg.Redactor != nil {
+			client.redactor = cfg.Redactor
+		}
+		if cfg.lookups == nil {
+			cfg.lookups = newLookups(cfg.IdentityNowClient, newReadCache(cfg.ReadCacheTTL, cfg.ReadCacheMaxEntries))
+		}
+		client.lookups = cfg.lookups
+		if cfg.APIVersion != "" {
+			client.apiVersions = apiVersions{defaultVersion: cfg.APIVersion, overrides: cfg.APIVersionOverrides}
+		}
+
+		if cfg.credentialStates == nil {
+			cfg.credentialStates = make(map[int]*credentialState)
+		}
+		if state, ok := cfg.credentialStates[credentialIndex]; ok {
+			client.tokens = state.tokens
+			client.health = state.health
+			client.rateLimiter = state.rateLimiter
+		} else {
+			// Pre-issued tokens are never fetched, so only client credentials use the persistent cache
+			if cfg.TokenCache != nil && client.authMode() == AuthModeClientCredentials {
+				client.tokens.usePersistentCache(cfg.TokenCache, client.BaseURL, client.clientId)
+			}
+			cfg.credentialStates[credentialIndex] = &credentialState{
+				tokens:      client.tokens,
+				health:      client.health,
+				rateLimiter: client.rateLimiter,
+			}
+		}
+
+		cfg.clients[clientIndex] = client
+	}
+
+	return cfg.clients[clientIndex], clientIndex
+}
+
+// IdentityNowClient returns a Client with a valid access token. Credentials whose
+// token cannot be obtained are ejected and the next healthy credential is tried.
+func (cfg *Config) IdentityNowClient(ctx context.Context) (*Client, error) {
+	tflog.Debug(ctx, "Client pool stats", cfg.GetClientPoolStats(ctx))
+
+	failed := make(map[int]bool)
+	var lastErr error
+	for len(failed) < len(cfg.Credentials) {
+		client, clientIndex := cfg.nextClient(ctx, failed)
+		if failed[cfg.credentialIndexFor(clientIndex)] {
+			// Every credential reachable from the pool has failed
+			break
+		}
+
+		// Concurrent callers for the same credential share one in-flight token refresh
+		if _, err := client.tokens.Token(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			tflog.Warn(ctx, "Failed to get OAuth token for client, trying next credential", map[string]interface{}{
+				"client_index": clientIndex,
+				"client_id":    client.clientId,
+				"error":        err.Error(),
+			})
+			client.health.recordTokenError(err)
+			failed[cfg.credentialIndexFor(clientIndex)] = true
+			lastErr = err
+			continue
+		}
+
+		tflog.Debug(ctx, "Client ready with valid token", map[string]interface{}{
+			"client_index": clientIndex,
+			"token_expiry": client.tokens.Expiry().Format(time.RFC3339),
+		})
+
+		return client, nil
+	}
+
+	tflog.Error(ctx, "Failed to get OAuth token for every credential", map[string]interface{}{
+		"credentials": len(cfg.Credentials),
+		"error":       lastErr.Error(),
+	})
+	return nil, lastErr
+}
+
+// ResetClient clears all cached clients, forcing creation of new ones on next request
+func (cfg *Config) ResetClient() {
+	cfg.clientMux.Lock()
+	defer cfg.clientMux.Unlock()
+	cfg.clients = nil
+	cfg.clientIndex = 0
+}
+
+// SetClientPoolSize sets the size of the client pool (max 10)
+func (cfg *Config) SetClientPoolSize(size int) {
+	cfg.clientMux.Lock()
+	defer cfg.clientMux.Unlock()
+
+	if size > cfg.MaxClientPoolSize {
+		size = cfg.MaxClientPoolSize
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	cfg.clientPoolSize = size
+	// Reset the pool when size changes
+	cfg.clients = nil
+	cfg.clientIndex = 0
+}
+
+// GetClientPoolStats returns statistics about the client pool
+func (cfg *Config) GetClientPoolStats(ctx context.Context) map[string]interface{} {
+	cfg.clientMux.Lock()
+	defer cfg.clientMux.Unlock()
+
+	if cfg.clients == nil {
+		return map[string]interface{}{
+			"pool_size":      cfg.clientPoolSize,
+			"active_clients": 0,
+			"valid_tokens":   0,
+			"credentials":    cfg.credentialStats(),
+		}
+	}
+
+	activeClients := 0
+	validTokens := 0
+
+	for i, client := range cfg.clients {
+		if client != nil {
+			activeClients++
+			if client.IsTokenValid(ctx) {
+				validTokens++
+			}
+		}
+		tflog.Debug(ctx, "Client pool status", map[string]interface{}{
+			"index":       i,
+			"has_client":  client != nil,
+			"valid_token": client != nil && client.IsTokenValid(ctx),
+			"token_expiry": func() string {
+				if client != nil {
+					return client.tokens.Expiry().Format(time.RFC3339)
+				}
+				return "N/A"
+			}(),
+		})
+	}
+
+	return map[string]interface{}{
+		"pool_size":      len(cfg.clients),
+		"active_clients": activeClients,
+		"valid_tokens":   validTokens,
+		"current_index":  cfg.clientIndex,
+		"credentials":    cfg.credentialStats(),
+	}
+}
+
+// credentialStats returns the health of every credential, cfg.clientMux must be held
+func (cfg *Config) credentialStats() []map[string]interface{} {
+	now := time.Now()
+	stats := make([]map[string]interface{}, 0, len(cfg.Credentials))
+	for i, credential := range cfg.Credentials {
+		var entry map[string]interface{}
+		if state, ok := cfg.credentialStates[i]; ok {
+			entry = state.health.stats(now)
+		} else {
+			entry = newCredentialHealth().stats(now)
+		}
+		entry["client_id"] = credential.ClientId
+		stats = append(stats, entry)
+	}
+	return stats
+}
@@ -0,0 +1,48 @@
+package identitynow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdentityNowClientFailsOverFromBadCredential(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_secret") != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		URL: server.URL,
+		Credentials: []ClientCredential{
+			{ClientId: "revoked", ClientSecret: "bad"},
+			{ClientId: "valid", ClientSecret: "good"},
+		},
+		MaxClientPoolSize:      2,
+		DefaultClientPoolSize:  2,
+		ClientRequestRateLimit: 1000,
+	}
+
+	for i := 0; i < 4; i++ {
+		client, err := cfg.IdentityNowClient(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if client.clientId != "valid" {
+			t.Fatalf("expected the valid credential, got %q", client.clientId)
+		}
+	}
+
+	stats := cfg.GetClientPoolStats(context.Background())["credentials"].([]map[string]interface{})
+	if stats[0]["healthy"] != false || stats[0]["token_errors"] != 1 {
+		t.Fatalf("expected the revoked credential to be ejected, got %+v", stats[0])
+	}
+	if stats[1]["healthy"] != true {
+		t.Fatalf("expected the valid credential to be healthy, got %+v", stats[1])
+	}
+}
@@ -0,0 +1,149 @@
+package identitynow
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// maxConsecutiveFailures is how many failed requests in a row eject a credential
+	maxConsecutiveFailures = 3
+	// credentialCooldown is the first ejection period, it doubles on every further ejection
+	credentialCooldown = 30 * time.Second
+	// credentialCooldownMax caps the ejection period
+	credentialCooldownMax = 5 * time.Minute
+	// throttleCooldown is how long a credential is avoided after a 429
+	throttleCooldown = 10 * time.Second
+)
+
+// credentialHealth tracks the outcome of requests made with one credential so the
+// pool can temporarily eject it and route traffic to healthier credentials.
+type credentialHealth struct {
+	mu                  sync.Mutex
+	inFlight            int
+	requests            int
+	consecutiveFailures int
+	throttled           int
+	tokenErrors         int
+	ejections           int
+	ejectedUntil        time.Time
+	lastError           string
+}
+
+func newCredentialHealth() *credentialHealth {
+	return &credentialHealth{}
+}
+
+// begin marks the start of a request
+func (h *credentialHealth) begin() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inFlight++
+	h.requests++
+}
+
+// cancel ends a request started with begin without recording an outcome
+func (h *credentialHealth) cancel() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inFlight--
+}
+
+// end records the outcome of a request started with begin
+func (h *credentialHealth) end(statusCode int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inFlight--
+
+	switch {
+	case err != nil:
+		h.recordFailure(err.Error())
+	case statusCode == http.StatusTooManyRequests:
+		h.throttled++
+		h.lastError = http.StatusText(statusCode)
+		h.ejectFor(throttleCooldown)
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden, statusCode >= http.StatusInternalServerError:
+		h.recordFailure(http.StatusText(statusCode))
+	default:
+		// Other 4xx responses are caused by the request, not by the credential
+		h.consecutiveFailures = 0
+		h.ejections = 0
+	}
+}
+
+// recordTokenError ejects the credential right away, a failing token request usually means a revoked secret
+func (h *credentialHealth) recordTokenError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tokenErrors++
+	h.consecutiveFailures = maxConsecutiveFailures - 1
+	h.recordFailure(err.Error())
+}
+
+// recordFailure counts a failure and ejects the credential once the threshold is reached, h.mu must be held
+func (h *credentialHealth) recordFailure(message string) {
+	h.consecutiveFailures++
+	h.lastError = message
+	if h.consecutiveFailures < maxConsecutiveFailures {
+		return
+	}
+
+	cooldown := credentialCooldown << h.ejections
+	if cooldown <= 0 || cooldown > credentialCooldownMax {
+		cooldown = credentialCooldownMax
+	}
+	h.ejections++
+	h.ejectFor(cooldown)
+}
+
+// ejectFor keeps the credential out of rotation for at least the given duration, h.mu must be held
+func (h *credentialHealth) ejectFor(cooldown time.Duration) {
+	until := time.Now().Add(cooldown)
+	if until.After(h.ejectedUntil) {
+		h.ejectedUntil = until
+	}
+}
+
+// healthy reports whether the credential is currently in rotation
+func (h *credentialHealth) healthy(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return !now.Before(h.ejectedUntil)
+}
+
+// load returns the number of requests in flight and when the credential returns to rotation
+func (h *credentialHealth) load() (int, time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.inFlight, h.ejectedUntil
+}
+
+// stats returns the health data reported by GetClientPoolStats
+func (h *credentialHealth) stats(now time.Time) map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ejectedUntil := "N/A"
+	if now.Before(h.ejectedUntil) {
+		ejectedUntil = h.ejectedUntil.Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"healthy":              !now.Before(h.ejectedUntil),
+		"in_flight":            h.inFlight,
+		"requests":             h.requests,
+		"consecutive_failures": h.consecutiveFailures,
+		"throttled":            h.throttled,
+		"token_errors":         h.tokenErrors,
+		"ejections":            h.ejections,
+		"ejected_until":        ejectedUntil,
+		"last_error":           h.lastError,
+	}
+}
@@ -0,0 +1,7 @@
+// Package identitynow is a client of the SailPoint IdentityNow API, used by terraform-provider-identitynow
+// and importable on its own. It is versioned together with the provider module.
+//
+// NewClient creates a client for one credential. Config pools clients across several
+// credentials, failing over from credentials whose token cannot be obtained.
+// Each API area is described by a service interface, e.g. SourcesService, which *Client implements.
+package identitynow
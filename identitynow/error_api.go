@@ -0,0 +1,89 @@
+package identitynow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize bounds how much of an error response body is read
+const maxErrorBodySize = 1 << 20
+
+// APIErrorMessage is a single localized message of an IdentityNow error response
+type APIErrorMessage struct {
+	Locale       string `json:"locale"`
+	LocaleOrigin string `json:"localeOrigin"`
+	Text         string `json:"text"`
+}
+
+// APIError is returned for every non-2xx response of the IdentityNow API.
+// Use errors.As to inspect the status code, detail code and tracking id.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	DetailCode string
+	TrackingID string
+	Messages   []APIErrorMessage
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s returned %d", e.Method, e.URL, e.StatusCode)
+	if e.DetailCode != "" {
+		fmt.Fprintf(&sb, " %s", e.DetailCode)
+	}
+
+	texts := e.messageTexts()
+	if len(texts) == 0 {
+		texts = []string{http.StatusText(e.StatusCode)}
+	}
+	fmt.Fprintf(&sb, ": %s", strings.Join(texts, "; "))
+
+	if e.TrackingID != "" {
+		fmt.Fprintf(&sb, " (tracking id: %s)", e.TrackingID)
+	}
+	return sb.String()
+}
+
+// messageTexts returns the distinct message texts, since the API repeats a message per locale
+func (e *APIError) messageTexts() []string {
+	seen := make(map[string]bool)
+	var texts []string
+	for _, m := range e.Messages {
+		if m.Text == "" || seen[m.Text] {
+			continue
+		}
+		seen[m.Text] = true
+		texts = append(texts, m.Text)
+	}
+	return texts
+}
+
+// newAPIError builds an APIError from a failed response, consuming its body.
+// requestURL is passed explicitly so callers can strip credentials from it.
+func newAPIError(method string, requestURL string, res *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Method:     method,
+		URL:        requestURL,
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err == nil && len(body) > 0 {
+		var errRes errorResponse
+		if json.Unmarshal(body, &errRes) == nil {
+			apiErr.DetailCode = errRes.DetailCode
+			apiErr.TrackingID = errRes.TrackingID
+			apiErr.Messages = errRes.Messages
+		}
+	}
+
+	if apiErr.TrackingID == "" {
+		apiErr.TrackingID = res.Header.Get("SLPT-Request-ID")
+	}
+
+	return apiErr
+}
@@ -0,0 +1,29 @@
+package identitynow
+
+import "errors"
+
+type NotFoundError struct {
+	message string
+	apiErr  *APIError
+}
+
+// NewNotFoundError returns the error of a missing object, e.g. for fakes of the service interfaces
+func NewNotFoundError(message string) *NotFoundError {
+	return &NotFoundError{message: message}
+}
+
+func (e *NotFoundError) Error() string { return e.message }
+
+// Unwrap exposes the underlying APIError, if the error came from an API response
+func (e *NotFoundError) Unwrap() error {
+	if e.apiErr == nil {
+		return nil
+	}
+	return e.apiErr
+}
+
+// IsNotFound reports whether err is, or wraps, a NotFoundError
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,14 @@
+package identitynow
+
+import "fmt"
+
+// ReadOnlyError is returned for requests that would change the tenant while read_only is set.
+// Such requests are refused before they are sent.
+type ReadOnlyError struct {
+	Method string
+	URL    string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("the provider is read-only, refusing to send %s %s", e.Method, e.URL)
+}
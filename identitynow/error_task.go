@@ -0,0 +1,27 @@
+package identitynow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskError is returned when an asynchronous task finishes with ERROR, TEMP_ERROR or TERMINATED
+type TaskError struct {
+	TaskID           string
+	Name             string
+	CompletionStatus string
+	Messages         []string
+}
+
+func (e *TaskError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "task %s", e.TaskID)
+	if e.Name != "" {
+		fmt.Fprintf(&sb, " (%s)", e.Name)
+	}
+	fmt.Fprintf(&sb, " finished with %s", e.CompletionStatus)
+	if len(e.Messages) > 0 {
+		fmt.Fprintf(&sb, ": %s", strings.Join(e.Messages, "; "))
+	}
+	return sb.String()
+}
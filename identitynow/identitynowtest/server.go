@@ -0,0 +1,941 @@
+// Package identitynowtest provides an in-memory IdentityNow tenant for tests of code using the identitynow package
+package identitynowtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// The only client credential a Server accepts
+const (
+	ClientID     = "fake-client-id"
+	ClientSecret = "fake-client-secret"
+)
+
+const fakeMaxPageSize = 250
+
+// Collections that reject requests without the X-SailPoint-Experimental header, like the real API does
+var fakeExperimentalCollections = map[string]bool{
+	"workgroups":  true,
+	"source-apps": true,
+}
+
+// Top level collections whose objects must have a unique name
+var fakeUniqueNameCollections = map[string]bool{
+	"sources":           true,
+	"access-profiles":   true,
+	"roles":             true,
+	"workgroups":        true,
+	"source-apps":       true,
+	"workflows":         true,
+	"password-policies": true,
+}
+
+// Server is a stateful in-memory stand-in for the v2025 endpoints used by the provider.
+// Objects live in collections keyed by their URL path without the id, e.g. "roles/<id>/dimensions",
+// so every list, create, read, replace, patch and delete call works the same for all of them.
+// Only the endpoints that do not follow that shape (OAuth, workgroup members, source app
+// access profiles, tagged objects) are handled separately.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	nextID      int
+	tokens      map[string]bool
+	collections map[string][]*fakeObject
+	members     map[string][]map[string]interface{}
+	tasks       map[string]*fakeTask
+	requests    []string
+
+	// FailTasks makes asynchronous tasks finish with ERROR instead of applying their change.
+	// Set it before sending requests.
+	FailTasks bool
+}
+
+// fakeTask is an asynchronous task, it completes after taskPolls status requests
+type fakeTask struct {
+	status   map[string]interface{}
+	polls    int
+	complete func()
+}
+
+// fakeTaskPolls is how many status requests a task stays pending for
+const fakeTaskPolls = 2
+
+type fakeObject struct {
+	id   string
+	data map[string]interface{}
+}
+
+// NewServer starts a fake tenant that accepts the ClientID/ClientSecret credential, it is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	f := &Server{
+		tokens:      map[string]bool{},
+		collections: map[string][]*fakeObject{},
+		members:     map[string][]map[string]interface{}{},
+		tasks:       map[string]*fakeTask{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// Seed stores an object that the provider cannot create itself, e.g. identities or entitlements
+func (f *Server) Seed(collection string, data map[string]interface{}) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.create(collection, data)
+}
+
+// Object returns a copy of a stored object, or nil if it does not exist
+func (f *Server) Object(collection string, id string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if obj := f.find(collection, id); obj != nil {
+		return fakeCopy(obj.data).(map[string]interface{})
+	}
+	return nil
+}
+
+// RequestCount returns how many requests were made with the given method and path
+func (f *Server) RequestCount(method string, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, r := range f.requests {
+		if r == method+" "+path {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	if r.URL.Path == "/oauth/token" {
+		f.serveToken(w, r)
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !f.tokens[token] {
+		fakeError(w, http.StatusUnauthorized, "401 Unauthorized", "invalid or expired access token")
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/v2025/") {
+		fakeError(w, http.StatusNotFound, "404 Not found", fmt.Sprintf("no endpoint %s", r.URL.Path))
+		return
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2025/"), "/"), "/")
+
+	if fakeExperimentalCollections[segments[0]] && r.Header.Get("X-SailPoint-Experimental") != "true" {
+		fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", "this endpoint requires the X-SailPoint-Experimental header")
+		return
+	}
+
+	// Parents of nested collections must exist, tagged objects are keyed by type instead
+	if len(segments) > 2 && segments[0] != "tagged-objects" && f.find(segments[0], segments[1]) == nil {
+		fakeError(w, http.StatusNotFound, "404 Not found", fmt.Sprintf("%s %s not found", segments[0], segments[1]))
+		return
+	}
+
+	switch {
+	case segments[0] == "workgroups" && len(segments) >= 3 && segments[2] == "members":
+		f.serveMembers(w, r, segments[1], segments[3:])
+	case segments[0] == "source-apps" && len(segments) == 2 && segments[1] == "all":
+		f.serveList(w, r, "source-apps", f.collections["source-apps"])
+	case segments[0] == "source-apps" && len(segments) >= 3 && segments[2] == "access-profiles":
+		f.serveSourceAppAccessProfiles(w, r, segments[1], segments[3:])
+	case segments[0] == "task-status" && len(segments) == 2:
+		f.serveTaskStatus(w, segments[1])
+	case segments[0] == "tagged-objects" && len(segments) == 3 && r.Method == http.MethodPut:
+		f.serveTaggedObject(w, r, segments[1], segments[2])
+	case len(segments)%2 == 1:
+		f.serveCollection(w, r, strings.Join(segments, "/"))
+	default:
+		f.serveObject(w, r, strings.Join(segments[:len(segments)-1], "/"), segments[len(segments)-1])
+	}
+}
+
+func (f *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		fakeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed", "token requests must use POST")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		fakeError(w, http.StatusBadRequest, "400.0 Bad Request", err.Error())
+		return
+	}
+	if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != ClientID || r.Form.Get("client_secret") != ClientSecret {
+		fakeError(w, http.StatusUnauthorized, "401 Unauthorized", "bad client credentials")
+		return
+	}
+
+	f.nextID++
+	token := fmt.Sprintf("fake-token-%d", f.nextID)
+	f.tokens[token] = true
+	fakeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   43199,
+	})
+}
+
+func (f *Server) serveCollection(w http.ResponseWriter, r *http.Request, collection string) {
+	switch r.Method {
+	case http.MethodGet:
+		f.serveList(w, r, collection, f.collections[collection])
+	case http.MethodPost:
+		data, ok := fakeDecodeObject(w, r)
+		if !ok {
+			return
+		}
+		if !f.checkUniqueName(w, collection, "", data) {
+			return
+		}
+		id := f.create(collection, data)
+		fakeJSON(w, http.StatusCreated, f.find(collection, id).data)
+	case http.MethodPut:
+		// Password policies are replaced through the collection with the id in the body
+		data, ok := fakeDecodeObject(w, r)
+		if !ok {
+			return
+		}
+		id, _ := data["id"].(string)
+		f.replace(w, collection, id, data)
+	default:
+		fakeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed", r.Method+" is not supported here")
+	}
+}
+
+func (f *Server) serveObject(w http.ResponseWriter, r *http.Request, collection string, id string) {
+	obj := f.find(collection, id)
+	if obj == nil {
+		fakeError(w, http.StatusNotFound, "404 Not found", fmt.Sprintf("%s %s not found", collection, id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		fakeJSON(w, http.StatusOK, obj.data)
+	case http.MethodPut:
+		data, ok := fakeDecodeObject(w, r)
+		if !ok {
+			return
+		}
+		f.replace(w, collection, id, data)
+	case http.MethodPatch:
+		var ops []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", "body must be a JSON Patch array: "+err.Error())
+			return
+		}
+		patched, err := applyFakePatch(fakeCopy(obj.data).(map[string]interface{}), ops)
+		if err != nil {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", err.Error())
+			return
+		}
+		if !f.checkUniqueName(w, collection, id, patched) {
+			return
+		}
+		patched["id"] = obj.data["id"]
+		patched["modified"] = time.Now().UTC().Format(time.RFC3339)
+		obj.data = patched
+		fakeJSON(w, http.StatusOK, obj.data)
+	case http.MethodDelete:
+		if collection == "sources" {
+			// Sources are deleted by a task, they stay readable until it completes
+			fakeJSON(w, http.StatusAccepted, map[string]interface{}{
+				"type": "TASK_RESULT",
+				"id":   f.startTask("Source Deletion", func() { f.delete(collection, id) }),
+				"name": nil,
+			})
+			return
+		}
+		f.delete(collection, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed", r.Method+" is not supported here")
+	}
+}
+
+// startTask registers a task that applies complete once it finishes
+func (f *Server) startTask(name string, complete func()) string {
+	f.nextID++
+	id := fmt.Sprintf("2c9180%026x", f.nextID)
+	f.tasks[id] = &fakeTask{
+		status: map[string]interface{}{
+			"id":               id,
+			"type":             "QUARTZ",
+			"uniqueName":       name,
+			"launched":         time.Now().UTC().Format(time.RFC3339),
+			"completed":        nil,
+			"completionStatus": nil,
+			"messages":         []interface{}{},
+		},
+		complete: complete,
+	}
+	return id
+}
+
+func (f *Server) serveTaskStatus(w http.ResponseWriter, id string) {
+	task, ok := f.tasks[id]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "404 Not found", fmt.Sprintf("task %s not found", id))
+		return
+	}
+
+	task.polls++
+	if task.polls > fakeTaskPolls && task.status["completionStatus"] == nil {
+		task.status["completed"] = time.Now().UTC().Format(time.RFC3339)
+		if f.FailTasks {
+			task.status["completionStatus"] = "ERROR"
+			task.status["messages"] = []interface{}{map[string]interface{}{
+				"type":          "ERROR",
+				"localizedText": map[string]interface{}{"locale": "en-US", "message": "the object is still referenced"},
+			}}
+		} else {
+			task.status["completionStatus"] = "SUCCESS"
+			task.complete()
+		}
+	}
+	fakeJSON(w, http.StatusOK, task.status)
+}
+
+// serveTaggedObject upserts the tags of an object, tagged objects have no id of their own
+func (f *Server) serveTaggedObject(w http.ResponseWriter, r *http.Request, objectType string, objectID string) {
+	data, ok := fakeDecodeObject(w, r)
+	if !ok {
+		return
+	}
+
+	collection := "tagged-objects/" + objectType
+	if obj := f.find(collection, objectID); obj != nil {
+		obj.data = data
+	} else {
+		f.collections[collection] = append(f.collections[collection], &fakeObject{id: objectID, data: data})
+	}
+	fakeJSON(w, http.StatusOK, data)
+}
+
+func (f *Server) serveMembers(w http.ResponseWriter, r *http.Request, workgroupID string, rest []string) {
+	members := f.members[workgroupID]
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		objects := make([]*fakeObject, 0, len(members))
+		for _, m := range members {
+			objects = append(objects, &fakeObject{data: m})
+		}
+		f.serveList(w, r, "members", objects)
+	case len(rest) == 1 && r.Method == http.MethodPost && (rest[0] == "bulk-add" || rest[0] == "bulk-delete"):
+		var refs []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&refs); err != nil {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", err.Error())
+			return
+		}
+
+		results := make([]map[string]interface{}, 0, len(refs))
+		for _, ref := range refs {
+			id, _ := ref["id"].(string)
+			index := -1
+			for i, m := range members {
+				if m["id"] == id {
+					index = i
+				}
+			}
+
+			status := http.StatusCreated
+			if rest[0] == "bulk-add" {
+				if index < 0 {
+					members = append(members, ref)
+				}
+			} else if index < 0 {
+				status = http.StatusNotFound
+			} else {
+				members = append(members[:index], members[index+1:]...)
+				status = http.StatusNoContent
+			}
+			results = append(results, map[string]interface{}{"id": id, "status": status})
+		}
+		f.members[workgroupID] = members
+		fakeJSON(w, http.StatusMultiStatus, results)
+	default:
+		fakeError(w, http.StatusNotFound, "404 Not found", "no endpoint "+r.URL.Path)
+	}
+}
+
+// serveSourceAppAccessProfiles resolves the accessProfiles id list of a source app
+func (f *Server) serveSourceAppAccessProfiles(w http.ResponseWriter, r *http.Request, appID string, rest []string) {
+	app := f.find("source-apps", appID)
+	ids, _ := app.data["accessProfiles"].([]interface{})
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		objects := make([]*fakeObject, 0, len(ids))
+		for _, id := range ids {
+			profile := map[string]interface{}{"id": id}
+			if obj := f.find("access-profiles", fmt.Sprint(id)); obj != nil {
+				profile = obj.data
+			}
+			objects = append(objects, &fakeObject{data: profile})
+		}
+		f.serveList(w, r, "access-profiles", objects)
+	case len(rest) == 1 && rest[0] == "bulk-remove" && r.Method == http.MethodPost:
+		var remove []string
+		if err := json.NewDecoder(r.Body).Decode(&remove); err != nil {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", err.Error())
+			return
+		}
+		kept := []interface{}{}
+		for _, id := range ids {
+			if !fakeContains(remove, fmt.Sprint(id)) {
+				kept = append(kept, id)
+			}
+		}
+		app.data["accessProfiles"] = kept
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusNotFound, "404 Not found", "no endpoint "+r.URL.Path)
+	}
+}
+
+// serveList filters and pages a list the way the v2025 list endpoints do
+func (f *Server) serveList(w http.ResponseWriter, r *http.Request, collection string, objects []*fakeObject) {
+	query := r.URL.Query()
+
+	limit := fakeMaxPageSize
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > fakeMaxPageSize {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", fmt.Sprintf("limit must be between 0 and %d", fakeMaxPageSize))
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if v := query.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", "offset must not be negative")
+			return
+		}
+		offset = n
+	}
+
+	matches := []map[string]interface{}{}
+	filter := func(map[string]interface{}) bool { return true }
+	if v := query.Get("filters"); v != "" {
+		var err error
+		if filter, err = parseFakeFilter(v); err != nil {
+			fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", fmt.Sprintf("invalid filter for %s: %s", collection, err))
+			return
+		}
+	}
+	for _, obj := range objects {
+		if filter(obj.data) {
+			matches = append(matches, obj.data)
+		}
+	}
+
+	if query.Get("count") == "true" {
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(matches)))
+	}
+	page := []map[string]interface{}{}
+	if offset < len(matches) {
+		end := offset + limit
+		if end > len(matches) {
+			end = len(matches)
+		}
+		page = matches[offset:end]
+	}
+	fakeJSON(w, http.StatusOK, page)
+}
+
+// create stores a new object and returns its id, f.mu must be held
+func (f *Server) create(collection string, data map[string]interface{}) string {
+	f.nextID++
+	id := fmt.Sprintf("2c9180%026x", f.nextID)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	data["id"] = id
+	if _, ok := data["created"]; !ok {
+		data["created"] = now
+	}
+	data["modified"] = now
+
+	// New sources come with their account and group schemas, like on a real tenant
+	if collection == "sources" {
+		var refs []interface{}
+		for _, name := range []string{"account", "group"} {
+			schemaID := f.create("sources/"+id+"/schemas", map[string]interface{}{"name": name, "nativeObjectType": name})
+			refs = append(refs, map[string]interface{}{"type": "CONNECTOR_SCHEMA", "id": schemaID, "name": name})
+		}
+		data["schemas"] = refs
+	}
+
+	f.collections[collection] = append(f.collections[collection], &fakeObject{id: id, data: data})
+	return id
+}
+
+// replace overwrites an object keeping its id and creation time, f.mu must be held
+func (f *Server) replace(w http.ResponseWriter, collection string, id string, data map[string]interface{}) {
+	obj := f.find(collection, id)
+	if obj == nil {
+		fakeError(w, http.StatusNotFound, "404 Not found", fmt.Sprintf("%s %s not found", collection, id))
+		return
+	}
+	if !f.checkUniqueName(w, collection, id, data) {
+		return
+	}
+
+	data["id"] = obj.data["id"]
+	data["created"] = obj.data["created"]
+	data["modified"] = time.Now().UTC().Format(time.RFC3339)
+	obj.data = data
+	fakeJSON(w, http.StatusOK, data)
+}
+
+// delete removes an object together with everything nested below it, f.mu must be held
+func (f *Server) delete(collection string, id string) {
+	objects := f.collections[collection]
+	for i, obj := range objects {
+		if obj.id == id {
+			f.collections[collection] = append(objects[:i:i], objects[i+1:]...)
+			break
+		}
+	}
+
+	prefix := collection + "/" + id + "/"
+	for name := range f.collections {
+		if strings.HasPrefix(name, prefix) {
+			delete(f.collections, name)
+		}
+	}
+	if collection == "workgroups" {
+		delete(f.members, id)
+	}
+}
+
+// find returns a stored object, f.mu must be held
+func (f *Server) find(collection string, id string) *fakeObject {
+	for _, obj := range f.collections[collection] {
+		if obj.id == id {
+			return obj
+		}
+	}
+	return nil
+}
+
+func (f *Server) checkUniqueName(w http.ResponseWriter, collection string, id string, data map[string]interface{}) bool {
+	name, _ := data["name"].(string)
+	if !fakeUniqueNameCollections[collection] || name == "" {
+		return true
+	}
+	for _, obj := range f.collections[collection] {
+		if obj.id != id && strings.EqualFold(fmt.Sprint(obj.data["name"]), name) {
+			fakeError(w, http.StatusBadRequest, "400.1.409 Reference conflict", fmt.Sprintf("an object named %q already exists in %s", name, collection))
+			return false
+		}
+	}
+	return true
+}
+
+func fakeDecodeObject(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil || data == nil {
+		fakeError(w, http.StatusBadRequest, "400.1 Bad Request Content", "body must be a JSON object")
+		return nil, false
+	}
+	return data, true
+}
+
+func fakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, detailCode string, text string) {
+	fakeJSON(w, status, map[string]interface{}{
+		"detailCode": detailCode,
+		"trackingId": fmt.Sprintf("fake-%d", time.Now().UnixNano()),
+		"messages": []map[string]interface{}{
+			{"locale": "en-US", "localeOrigin": "DEFAULT", "text": text},
+		},
+	})
+}
+
+// fakeCopy deep copies decoded JSON so callers cannot modify stored objects
+func fakeCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			c[key] = fakeCopy(item)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = fakeCopy(item)
+		}
+		return c
+	}
+	return v
+}
+
+func fakeContains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFakePatch applies the add, replace and remove operations of a JSON Patch (RFC 6902).
+// Like the real API, add creates missing parent objects.
+func applyFakePatch(doc map[string]interface{}, ops []map[string]interface{}) (map[string]interface{}, error) {
+	for _, op := range ops {
+		name, _ := op["op"].(string)
+		path, _ := op["path"].(string)
+		if name != "add" && name != "replace" && name != "remove" {
+			return nil, fmt.Errorf("unsupported patch operation %q", name)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid patch path %q", path)
+		}
+
+		var tokens []string
+		for _, token := range strings.Split(path[1:], "/") {
+			tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+		if _, err := patchFakeValue(doc, tokens, name, op["value"]); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", name, path, err)
+		}
+	}
+	return doc, nil
+}
+
+func patchFakeValue(node interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	token, rest := tokens[0], tokens[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, exists := n[token]
+		if len(rest) == 0 {
+			switch {
+			case op == "add":
+				n[token] = value
+			case !exists:
+				return nil, fmt.Errorf("path not found")
+			case op == "replace":
+				n[token] = value
+			default:
+				delete(n, token)
+			}
+			return n, nil
+		}
+		if child == nil {
+			if op != "add" {
+				return nil, fmt.Errorf("path not found")
+			}
+			child = map[string]interface{}{}
+		}
+		updated, err := patchFakeValue(child, rest, op, value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = updated
+		return n, nil
+	case []interface{}:
+		index := len(n)
+		if token != "-" {
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i > len(n) || (i == len(n) && (op != "add" || len(rest) > 0)) {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			index = i
+		} else if op != "add" || len(rest) > 0 {
+			return nil, fmt.Errorf("invalid array index %q", token)
+		}
+
+		if len(rest) > 0 {
+			updated, err := patchFakeValue(n[index], rest, op, value)
+			if err != nil {
+				return nil, err
+			}
+			n[index] = updated
+			return n, nil
+		}
+		switch op {
+		case "add":
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+		case "replace":
+			n[index] = value
+		default:
+			n = append(n[:index], n[index+1:]...)
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("path not found")
+}
+
+// parseFakeFilter compiles the subset of the v2025 filter syntax used by the provider:
+// eq, ne, in, sw, co, pr and isnull comparisons combined with and, or, not and parentheses.
+func parseFakeFilter(filter string) (func(map[string]interface{}) bool, error) {
+	tokens, err := tokenizeFakeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &fakeFilterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type fakeFilterToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeFakeFilter(filter string) ([]fakeFilterToken, error) {
+	var tokens []fakeFilterToken
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, fakeFilterToken{text: string(r)})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, fakeFilterToken{text: b.String(), quoted: true})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, fakeFilterToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type fakeFilterParser struct {
+	tokens []fakeFilterToken
+	pos    int
+}
+
+func (p *fakeFilterParser) peek(keyword string) bool {
+	return p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *fakeFilterParser) next() (fakeFilterToken, error) {
+	if p.pos == len(p.tokens) {
+		return fakeFilterToken{}, fmt.Errorf("unexpected end of filter")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *fakeFilterParser) expect(text string) error {
+	if !p.peek(text) {
+		return fmt.Errorf("expected %q", text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *fakeFilterParser) parseOr() (func(map[string]interface{}) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(obj map[string]interface{}) bool { return l(obj) || right(obj) }
+	}
+	return left, nil
+}
+
+func (p *fakeFilterParser) parseAnd() (func(map[string]interface{}) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(obj map[string]interface{}) bool { return l(obj) && right(obj) }
+	}
+	return left, nil
+}
+
+func (p *fakeFilterParser) parseUnary() (func(map[string]interface{}) bool, error) {
+	if p.peek("not") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(obj map[string]interface{}) bool { return !inner(obj) }, nil
+	}
+	if p.peek("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+func (p *fakeFilterParser) parseComparison() (func(map[string]interface{}) bool, error) {
+	field, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	operator, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(operator.text)
+
+	switch op {
+	case "pr":
+		return func(obj map[string]interface{}) bool { return len(fakeFieldValues(obj, field.text)) > 0 }, nil
+	case "isnull":
+		return func(obj map[string]interface{}) bool { return len(fakeFieldValues(obj, field.text)) == 0 }, nil
+	case "in":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var values []string
+		for {
+			value, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value.text)
+			if p.peek(")") {
+				p.pos++
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		return func(obj map[string]interface{}) bool {
+			for _, actual := range fakeFieldValues(obj, field.text) {
+				for _, value := range values {
+					if strings.EqualFold(actual, value) {
+						return true
+					}
+				}
+			}
+			return false
+		}, nil
+	case "eq", "ne", "sw", "co":
+		value, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		match := func(actual string) bool {
+			actual, expected := strings.ToLower(actual), strings.ToLower(value.text)
+			switch op {
+			case "sw":
+				return strings.HasPrefix(actual, expected)
+			case "co":
+				return strings.Contains(actual, expected)
+			}
+			return actual == expected
+		}
+		return func(obj map[string]interface{}) bool {
+			found := false
+			for _, actual := range fakeFieldValues(obj, field.text) {
+				found = found || match(actual)
+			}
+			return found != (op == "ne")
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported filter operator %q", operator.text)
+}
+
+// fakeFieldValues returns the scalar values found at a dotted field path, flattening arrays
+func fakeFieldValues(obj map[string]interface{}, field string) []string {
+	values := []interface{}{obj}
+	for _, key := range strings.Split(field, ".") {
+		var next []interface{}
+		for _, v := range values {
+			if m, ok := v.(map[string]interface{}); ok && m[key] != nil {
+				next = append(next, m[key])
+			}
+		}
+		values = nil
+		for _, v := range next {
+			if list, ok := v.([]interface{}); ok {
+				values = append(values, list...)
+			} else {
+				values = append(values, v)
+			}
+		}
+	}
+
+	var result []string
+	for _, v := range values {
+		switch v.(type) {
+		case map[string]interface{}, nil:
+		default:
+			result = append(result, fmt.Sprint(v))
+		}
+	}
+	return result
+}
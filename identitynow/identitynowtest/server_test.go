@@ -0,0 +1,62 @@
+package identitynowtest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFakeFilter(t *testing.T) {
+	obj := map[string]interface{}{
+		"name":   "Payroll Admin",
+		"source": map[string]interface{}{"id": "src1"},
+		"tags":   []interface{}{"PCI", "SOX"},
+	}
+
+	for filter, expected := range map[string]bool{
+		`name eq "payroll admin"`:                           true,
+		`source.id eq "src1" and (name eq "Payroll Admin")`: true,
+		`source.id eq "src2" or name sw "Pay"`:              true,
+		`name in ("a", "Payroll Admin")`:                    true,
+		`tags co "pc" and not name ne "Payroll Admin"`:      true,
+		`owner pr`:                             false,
+		`owner isnull and source.id ne "src1"`: false,
+	} {
+		match, err := parseFakeFilter(filter)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", filter, err)
+		}
+		if match(obj) != expected {
+			t.Errorf("%s: expected %v", filter, expected)
+		}
+	}
+
+	if _, err := parseFakeFilter(`name eq`); err == nil {
+		t.Error("expected an error for an incomplete filter")
+	}
+}
+
+func TestFakePatch(t *testing.T) {
+	doc := map[string]interface{}{
+		"name": "role",
+		"list": []interface{}{"a", "c"},
+	}
+	_, err := applyFakePatch(doc, []map[string]interface{}{
+		{"op": "add", "path": "/list/1", "value": "b"},
+		{"op": "add", "path": "/list/-", "value": "d"},
+		{"op": "replace", "path": "/name", "value": "renamed"},
+		{"op": "add", "path": "/connectorAttributes/a~1b", "value": true},
+		{"op": "remove", "path": "/list/0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `{"connectorAttributes":{"a/b":true},"list":["b","c","d"],"name":"renamed"}`
+	if actual, _ := json.Marshal(doc); string(actual) != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+
+	if _, err := applyFakePatch(doc, []map[string]interface{}{{"op": "replace", "path": "/missing", "value": 1}}); err == nil {
+		t.Fatal("expected an error when replacing a missing value")
+	}
+}
@@ -0,0 +1,297 @@
+package identitynow
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultReadCacheTTL        = 5 * time.Minute
+	DefaultReadCacheMaxEntries = 1000
+
+	// lookupBatchWindow is how long a lookup waits for concurrent lookups to share its request
+	lookupBatchWindow = 10 * time.Millisecond
+	// lookupBatchMaxKeys keeps batched filters well below URL length limits
+	lookupBatchMaxKeys = 50
+)
+
+// readCache keeps the results of lookups that do not change during a run, e.g. identities by email.
+// It is bounded by ttl and maxEntries, the least recently used entry is evicted first.
+type readCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type readCacheEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+func newReadCache(ttl time.Duration, maxEntries int) *readCache {
+	return &readCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns a cached value that has not expired
+func (c *readCache) Get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*readCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Put stores a value, evicting the least recently used entries above maxEntries
+func (c *readCache) Put(key string, value interface{}) {
+	if c == nil || c.ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &readCacheEntry{key: key, value: value, expires: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*readCacheEntry).key)
+	}
+}
+
+// lookupBatcher merges concurrent lookups of single keys into one request for all of them.
+// Keys are compared case-insensitively, like IdentityNow filters.
+type lookupBatcher[T any] struct {
+	// fetch returns the objects matching any of keys
+	fetch func(ctx context.Context, keys []string) ([]T, error)
+	// keysOf returns the values of an object that a lookup key can match
+	keysOf func(T) []string
+
+	mu      sync.Mutex
+	pending *lookupBatch[T]
+}
+
+type lookupBatch[T any] struct {
+	ctx     context.Context
+	keys    []string
+	seen    map[string]bool
+	done    chan struct{}
+	results map[string][]T
+	err     error
+}
+
+// Lookup returns the objects matching key, sharing the request with lookups made within lookupBatchWindow
+func (b *lookupBatcher[T]) Lookup(ctx context.Context, key string) ([]T, error) {
+	b.mu.Lock()
+	batch := b.pending
+	if batch == nil {
+		// The request outlives a caller that gives up, the other callers still wait for it
+		batch = &lookupBatch[T]{
+			ctx:  context.WithoutCancel(ctx),
+			seen: make(map[string]bool),
+			done: make(chan struct{}),
+		}
+		b.pending = batch
+		time.AfterFunc(lookupBatchWindow, func() { b.flush(batch) })
+	}
+	normalized := strings.ToLower(key)
+	if !batch.seen[normalized] {
+		batch.seen[normalized] = true
+		batch.keys = append(batch.keys, key)
+	}
+	if len(batch.keys) >= lookupBatchMaxKeys {
+		// Full batches are sent right away, later lookups start a new batch
+		b.pending = nil
+		go b.send(batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	if items, ok := batch.results[normalized]; ok {
+		return items, nil
+	}
+	return []T{}, nil
+}
+
+// flush sends a batch when its window ends, unless it was already sent because it was full
+func (b *lookupBatcher[T]) flush(batch *lookupBatch[T]) {
+	b.mu.Lock()
+	if b.pending != batch {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+
+	b.send(batch)
+}
+
+// send requests all keys of a batch and hands every caller its share of the results
+func (b *lookupBatcher[T]) send(batch *lookupBatch[T]) {
+	tflog.Debug(batch.ctx, "Sending batched lookup", map[string]interface{}{
+		"keys": len(batch.keys),
+	})
+	items, err := b.fetch(batch.ctx, batch.keys)
+	batch.err = err
+	batch.results = make(map[string][]T)
+	for _, item := range items {
+		for _, key := range b.keysOf(item) {
+			normalized := strings.ToLower(key)
+			if batch.seen[normalized] {
+				batch.results[normalized] = append(batch.results[normalized], item)
+			}
+		}
+	}
+	close(batch.done)
+}
+
+// FilterString quotes a value for a filters expression, escaping quotes and backslashes
+func FilterString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// inFilter builds a filter such as name in ("Admins", "Auditors")
+func inFilter(field string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = FilterString(value)
+	}
+	return fmt.Sprintf("%s in (%s)", field, strings.Join(quoted, ", "))
+}
+
+// eqAnyFilter builds a filter such as email eq "a@example.com" or email eq "b@example.com",
+// for fields that only support eq and sw, like the email and alias of identities
+func eqAnyFilter(field string, values []string) string {
+	comparisons := make([]string, len(values))
+	for i, value := range values {
+		comparisons[i] = field + " eq " + FilterString(value)
+	}
+	return strings.Join(comparisons, " or ")
+}
+
+// lookups holds the read cache and batchers shared by every pooled client of a provider instance
+type lookups struct {
+	cache *readCache
+	// client returns the pooled client that sends a batch
+	client func(ctx context.Context) (*Client, error)
+
+	identitiesByEmail *lookupBatcher[*Identity]
+	identitiesByAlias *lookupBatcher[*Identity]
+
+	entitlementsMux      sync.Mutex
+	entitlementsBySource map[string]*lookupBatcher[*SourceEntitlement]
+}
+
+// newLookups wires the batchers to the client pool. Each batch asks client for a pooled client,
+// so batched lookups follow the pool rotation and skip unhealthy credentials like any other request.
+func newLookups(client func(ctx context.Context) (*Client, error), cache *readCache) *lookups {
+	l := &lookups{
+		cache:                cache,
+		client:               client,
+		entitlementsBySource: make(map[string]*lookupBatcher[*SourceEntitlement]),
+	}
+	l.identitiesByEmail = &lookupBatcher[*Identity]{
+		fetch: func(ctx context.Context, keys []string) ([]*Identity, error) {
+			c, err := l.client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.listIdentities(ctx, eqAnyFilter("email", keys))
+		},
+		keysOf: func(identity *Identity) []string {
+			keys := []string{identity.EmailAddress}
+			if identity.IdentityAttributes != nil {
+				keys = append(keys, identity.IdentityAttributes.Email)
+			}
+			return keys
+		},
+	}
+	l.identitiesByAlias = &lookupBatcher[*Identity]{
+		fetch: func(ctx context.Context, keys []string) ([]*Identity, error) {
+			c, err := l.client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.listIdentities(ctx, eqAnyFilter("alias", keys))
+		},
+		keysOf: func(identity *Identity) []string { return []string{identity.Alias} },
+	}
+	return l
+}
+
+// entitlementBatcher returns the batcher of entitlement lookups by name within one source
+func (l *lookups) entitlementBatcher(sourceID string) *lookupBatcher[*SourceEntitlement] {
+	l.entitlementsMux.Lock()
+	defer l.entitlementsMux.Unlock()
+
+	if b, ok := l.entitlementsBySource[sourceID]; ok {
+		return b
+	}
+	b := &lookupBatcher[*SourceEntitlement]{
+		fetch: func(ctx context.Context, keys []string) ([]*SourceEntitlement, error) {
+			c, err := l.client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.listEntitlements(ctx, fmt.Sprintf("source.id eq %s and (%s)", FilterString(sourceID), inFilter("name", keys)))
+		},
+		keysOf: func(entitlement *SourceEntitlement) []string { return []string{entitlement.Name} },
+	}
+	l.entitlementsBySource[sourceID] = b
+	return b
+}
+
+// cachedLookup serves a lookup from the read cache, or through its batcher when not cached
+func cachedLookup[T any](ctx context.Context, l *lookups, cacheKey string, batcher *lookupBatcher[T], key string) ([]T, error) {
+	if cached, ok := l.cache.Get(cacheKey); ok {
+		tflog.Debug(ctx, "Lookup served from read cache", map[string]interface{}{
+			"key": cacheKey,
+		})
+		return cached.([]T), nil
+	}
+	items, err := batcher.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	l.cache.Put(cacheKey, items)
+	return items, nil
+}
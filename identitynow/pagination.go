@@ -0,0 +1,106 @@
+package identitynow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultPageSize is the maximum page size accepted by the v2025 collection endpoints
+	defaultPageSize = 250
+)
+
+// listOptions tunes how listAll walks a collection endpoint
+type listOptions[T any] struct {
+	// pageSize overrides the number of items requested per page
+	pageSize int
+	// stop ends paging early once it returns true for the latest page
+	stop func(page []T) bool
+}
+
+// withFilters adds a filters query parameter to a collection URL, unless filters is empty
+func withFilters(collectionURL string, filters string) string {
+	if filters == "" {
+		return collectionURL
+	}
+	return fmt.Sprintf("%s?filters=%s", collectionURL, url.QueryEscape(filters))
+}
+
+// listAll fetches every page of a collection endpoint using limit/offset paging.
+// It requests count=true and relies on X-Total-Count to know when it is done,
+// falling back to a short page when the header is missing.
+func listAll[T any](ctx context.Context, c *Client, rawURL string, opts listOptions[T]) ([]T, error) {
+	pageSize := opts.pageSize
+	if pageSize <= 0 || pageSize > defaultPageSize {
+		pageSize = defaultPageSize
+	}
+
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+	offset := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pageURL := *baseURL
+		query := pageURL.Query()
+		query.Set("limit", strconv.Itoa(pageSize))
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("count", "true")
+		pageURL.RawQuery = query.Encode()
+
+		tflog.Debug(ctx, "Creating HTTP request to list collection page", map[string]interface{}{
+			"method": "GET",
+			"url":    pageURL.String(),
+			"limit":  pageSize,
+			"offset": offset,
+		})
+		req, err := http.NewRequest("GET", pageURL.String(), nil)
+		if err != nil {
+			tflog.Error(ctx, "Failed to create new HTTP request", map[string]interface{}{"error": err.Error()})
+			return nil, err
+		}
+
+		req.Header.Set("Accept", "application/json; charset=utf-8")
+
+		req = req.WithContext(ctx)
+
+		var page []T
+		header, err := c.sendRequestWithHeader(ctx, req, &page)
+		if err != nil {
+			tflog.Error(ctx, "Request failed", map[string]interface{}{"response": fmt.Sprintf("%+v", page)})
+			return nil, err
+		}
+
+		items = append(items, page...)
+
+		if opts.stop != nil && opts.stop(page) {
+			break
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+			if offset+len(page) >= total {
+				break
+			}
+		} else if len(page) < pageSize {
+			break
+		}
+
+		offset += len(page)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,143 @@
+package identitynow
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// adaptiveBurstDivisor limits the burst to a fraction of the remaining budget
+const adaptiveBurstDivisor = 10
+
+var (
+	tenantLimitersMux sync.Mutex
+	tenantLimiters    = map[string]*adaptiveRateLimiter{}
+)
+
+// adaptiveRateLimiter paces every request sent to one tenant. Its rate and burst follow
+// the rate-limit headers of the latest response, and a 429 or an exhausted budget pauses
+// all pooled clients of the tenant until the window resets. The static per-credential
+// limiter configured by client_request_rate_limit still applies on top of it.
+type adaptiveRateLimiter struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// tenantRateLimiter returns the limiter shared by every client of the tenant behind baseURL
+func tenantRateLimiter(baseURL string) *adaptiveRateLimiter {
+	key := strings.ToLower(strings.TrimSuffix(baseURL, "/"))
+
+	tenantLimitersMux.Lock()
+	defer tenantLimitersMux.Unlock()
+
+	if l, ok := tenantLimiters[key]; ok {
+		return l
+	}
+	// No budget is known until the first response, so start unlimited
+	l := &adaptiveRateLimiter{limiter: rate.NewLimiter(rate.Inf, 1)}
+	tenantLimiters[key] = l
+	return l
+}
+
+// Wait blocks until the tenant budget allows another request
+func (l *adaptiveRateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	wait := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+
+	if wait > 0 {
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// Observe adjusts the limiter to the rate-limit headers of a response
+func (l *adaptiveRateLimiter) Observe(ctx context.Context, statusCode int, header http.Header) {
+	now := time.Now()
+
+	if statusCode == http.StatusTooManyRequests {
+		if wait, ok := waitFromHeaders(header, now); ok {
+			l.pause(ctx, now.Add(wait))
+		}
+	}
+
+	remaining, okRemaining := rateLimitHeader(header, "Remaining")
+	reset, okReset := rateLimitReset(header, now)
+	if !okRemaining || !okReset {
+		return
+	}
+
+	if remaining <= 0 {
+		l.pause(ctx, now.Add(reset))
+		return
+	}
+	if reset <= 0 {
+		return
+	}
+
+	// Spread the remaining budget evenly over the rest of the window
+	limit := rate.Limit(float64(remaining) / reset.Seconds())
+	burst := remaining / adaptiveBurstDivisor
+	if max, ok := rateLimitHeader(header, "Limit"); ok && max > 0 && burst > max {
+		burst = max
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.limiter.SetLimitAt(now, limit)
+	l.limiter.SetBurstAt(now, burst)
+
+	tflog.Trace(ctx, "Adjusted tenant rate limiter", map[string]interface{}{
+		"remaining": remaining,
+		"reset":     reset.String(),
+		"rate":      float64(limit),
+		"burst":     burst,
+	})
+}
+
+func (l *adaptiveRateLimiter) pause(ctx context.Context, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		tflog.Debug(ctx, "Pausing requests to tenant until rate limit window resets", map[string]interface{}{
+			"paused_until": until.Format(time.RFC3339),
+		})
+	}
+}
+
+// rateLimitHeader reads X-RateLimit-<name>, falling back to the RateLimit-<name> draft header
+func rateLimitHeader(header http.Header, name string) (int, bool) {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name} {
+		if v := header.Get(key); v != "" {
+			if value, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// rateLimitReset returns the time left in the current rate limit window
+func rateLimitReset(header http.Header, now time.Time) (time.Duration, bool) {
+	for _, key := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if v := header.Get(key); v != "" {
+			if wait, ok := parseRateLimitReset(strings.TrimSpace(v), now); ok {
+				return wait, true
+			}
+		}
+	}
+	return 0, false
+}
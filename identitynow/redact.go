@@ -0,0 +1,226 @@
+package identitynow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const redactedValue = "REDACTED"
+
+// defaultRedactionRules cover the secrets IdentityNow payloads carry, e.g. in source connectorAttributes
+var defaultRedactionRules = []string{
+	"password",
+	"secret",
+	"client_id",
+	"client_secret",
+	"clientSecret",
+	"IQServicePassword",
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"jti",
+	"privateKey",
+	"private_key",
+	"privateKeyPassword",
+	"apiKey",
+	"token",
+}
+
+// defaultRedactor is used by clients created without redaction_keys
+var defaultRedactor, _ = NewRedactor(nil)
+
+// redactionRule matches the location of a JSON value. Keys are compared case-insensitively,
+// array elements do not add a path segment.
+type redactionRule struct {
+	segments []string
+	// anyDepth rules match wherever their segments end the path
+	anyDepth bool
+}
+
+// parseRedactionRule accepts a key name, matched at any depth, or a JSON path such as
+// $.connectorAttributes.password, $..password or $.connectorAttributes.*
+func parseRedactionRule(rule string) (redactionRule, error) {
+	rule = strings.TrimSpace(rule)
+	var parsed redactionRule
+	switch {
+	case strings.HasPrefix(rule, "$.."):
+		parsed.anyDepth = true
+		rule = strings.TrimPrefix(rule, "$..")
+	case strings.HasPrefix(rule, "$."):
+		rule = strings.TrimPrefix(rule, "$.")
+	case strings.HasPrefix(rule, "$"):
+		return parsed, fmt.Errorf("redaction rule %q must start with $. or $..", rule)
+	default:
+		parsed.anyDepth = true
+	}
+
+	for _, segment := range strings.Split(rule, ".") {
+		if segment == "" {
+			return parsed, fmt.Errorf("redaction rule %q has an empty path segment", rule)
+		}
+		parsed.segments = append(parsed.segments, segment)
+	}
+	return parsed, nil
+}
+
+func (r redactionRule) matches(path []string) bool {
+	if len(path) < len(r.segments) || (!r.anyDepth && len(path) != len(r.segments)) {
+		return false
+	}
+	tail := path[len(path)-len(r.segments):]
+	for i, segment := range r.segments {
+		if segment != "*" && !strings.EqualFold(segment, tail[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Redactor removes secrets from payloads before they are logged or audited
+type Redactor struct {
+	rules []redactionRule
+	// keyFragments match every key containing one of them, e.g. bindPassword for password
+	keyFragments []string
+}
+
+// NewRedactor returns a redactor using the default rules plus the configured extra ones
+func NewRedactor(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, rule := range append(append([]string{}, defaultRedactionRules...), extra...) {
+		parsed, err := parseRedactionRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, parsed)
+	}
+	return r, nil
+}
+
+// withKeyFragments returns a copy of r that also redacts keys containing any of fragments
+func (r *Redactor) withKeyFragments(fragments ...string) *Redactor {
+	copied := *r
+	copied.keyFragments = append(append([]string{}, r.keyFragments...), fragments...)
+	return &copied
+}
+
+func (r *Redactor) matches(path []string) bool {
+	if len(path) > 0 {
+		key := strings.ToLower(path[len(path)-1])
+		for _, fragment := range r.keyFragments {
+			if strings.Contains(key, fragment) {
+				return true
+			}
+		}
+	}
+	for _, rule := range r.rules {
+		if rule.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBody decodes a request or response body and redacts it, bodies that cannot be decoded are never logged
+func (r *Redactor) redactBody(body []byte, contentType string) interface{} {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redactedValue
+		}
+		form := map[string]interface{}{}
+		for key, value := range values {
+			if r.matches([]string{key}) {
+				form[key] = redactedValue
+			} else {
+				form[key] = strings.Join(value, ",")
+			}
+		}
+		return form
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return redactedValue
+	}
+	if strings.HasPrefix(contentType, "application/json-patch+json") {
+		return r.redactPatch(payload)
+	}
+	return r.redact(payload, nil)
+}
+
+// loggable converts a decoded API object into a redacted value for logging
+func (r *Redactor) loggable(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redactedValue
+	}
+	return r.redactBody(data, "application/json")
+}
+
+// redactPatch redacts JSON Patch operations, using each operation path as the location of its value
+func (r *Redactor) redactPatch(payload interface{}) interface{} {
+	operations, ok := payload.([]interface{})
+	if !ok {
+		return r.redact(payload, nil)
+	}
+	for _, operation := range operations {
+		op, ok := operation.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, hasValue := op["value"]
+		opPath, _ := op["path"].(string)
+		if !hasValue {
+			continue
+		}
+		path := jsonPointerPath(opPath)
+		if len(path) > 0 && r.matches(path) {
+			op["value"] = redactedValue
+		} else {
+			op["value"] = r.redact(value, path)
+		}
+	}
+	return operations
+}
+
+func (r *Redactor) redact(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			fieldPath := append(append([]string{}, path...), key)
+			if r.matches(fieldPath) {
+				v[key] = redactedValue
+			} else {
+				v[key] = r.redact(field, fieldPath)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redact(item, path)
+		}
+	}
+	return value
+}
+
+// jsonPointerPath splits a JSON Patch path into keys, dropping array indexes
+func jsonPointerPath(pointer string) []string {
+	var path []string
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if segment == "" || segment == "-" {
+			continue
+		}
+		if _, err := strconv.Atoi(segment); err == nil {
+			continue
+		}
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		path = append(path, segment)
+	}
+	return path
+}
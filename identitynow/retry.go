@@ -0,0 +1,112 @@
+package identitynow
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 5
+	retryWaitMin      = 1 * time.Second
+	retryWaitMax      = 30 * time.Second
+	// Upper bound for server-provided wait hints so a bogus header cannot stall an apply
+	retryHeaderWaitMax = 5 * time.Minute
+)
+
+// isIdempotentMethod reports whether a request with the given method can be replayed safely
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry decides whether a response status is worth another attempt.
+// A 429 means the request was rejected before processing, so it is retried for every method.
+// Gateway errors may hide a request that was applied, so they are only retried for idempotent methods.
+func shouldRetry(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentMethod(method)
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the given retry attempt (starting at 1).
+// Retry-After and X-RateLimit-Reset headers take precedence over exponential backoff with jitter.
+func retryDelay(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := waitFromHeaders(res.Header, time.Now()); ok {
+			return wait
+		}
+	}
+
+	backoff := float64(retryWaitMin) * math.Pow(2, float64(attempt-1))
+	if backoff > float64(retryWaitMax) {
+		backoff = float64(retryWaitMax)
+	}
+	// Equal jitter: half of the backoff is fixed, the other half is random
+	half := time.Duration(backoff / 2)
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// waitFromHeaders parses Retry-After (seconds or HTTP date) and X-RateLimit-Reset (seconds or unix epoch)
+func waitFromHeaders(header http.Header, now time.Time) (time.Duration, bool) {
+	if v := header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return capHeaderWait(time.Duration(seconds) * time.Second), true
+		}
+		if date, err := http.ParseTime(v); err == nil {
+			return capHeaderWait(date.Sub(now)), true
+		}
+	}
+
+	if v := header.Get("X-RateLimit-Reset"); v != "" {
+		return parseRateLimitReset(v, now)
+	}
+
+	return 0, false
+}
+
+// parseRateLimitReset reads a rate limit reset value given in seconds or as a unix epoch
+func parseRateLimitReset(v string, now time.Time) (time.Duration, bool) {
+	value, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	// Large values are an absolute epoch timestamp, small ones a relative number of seconds
+	if value > 1000000000 {
+		return capHeaderWait(time.Unix(value, 0).Sub(now)), true
+	}
+	return capHeaderWait(time.Duration(value) * time.Second), true
+}
+
+func capHeaderWait(wait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	if wait > retryHeaderWaitMax {
+		return retryHeaderWaitMax
+	}
+	return wait
+}
+
+// sleepWithContext waits for the given duration or until the context is done
+func sleepWithContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,152 @@
+package identitynow
+
+import (
+	"context"
+)
+
+// SourcesService manages sources and what belongs to them, their account schemas and aggregation schedules
+type SourcesService interface {
+	ListSources(ctx context.Context, filters string) ([]*Source, error)
+	GetSourceByName(ctx context.Context, name string) ([]*Source, error)
+	GetSource(ctx context.Context, id string) (*Source, error)
+	CreateSource(ctx context.Context, source *Source) (*Source, error)
+	UpdateSource(ctx context.Context, source *Source) (*Source, error)
+	DeleteSource(ctx context.Context, source *Source) error
+
+	GetAccountSchema(ctx context.Context, sourceId string, id string) (*AccountSchema, error)
+	UpdateAccountSchema(ctx context.Context, accountSchema *AccountSchema) (*AccountSchema, error)
+	DeleteAccountSchema(ctx context.Context, accountSchema *AccountSchema) error
+
+	GetAccountAggregationSchedule(ctx context.Context, id string) (*AccountAggregationSchedule, error)
+	ManageAccountAggregationSchedule(ctx context.Context, scheduleAggregation *AccountAggregationSchedule, enable bool) (*AccountAggregationSchedule, error)
+}
+
+// EntitlementsService looks up the entitlements aggregated from sources
+type EntitlementsService interface {
+	GetSourceEntitlements(ctx context.Context, id string) ([]*SourceEntitlement, error)
+	GetSourceEntitlement(ctx context.Context, id string, nameFilter string) ([]*SourceEntitlement, error)
+}
+
+// AccessProfilesService manages access profiles
+type AccessProfilesService interface {
+	ListAccessProfiles(ctx context.Context, filters string) ([]*AccessProfile, error)
+	GetAccessProfileByName(ctx context.Context, name string) ([]*AccessProfile, error)
+	GetAccessProfile(ctx context.Context, id string) (*AccessProfile, error)
+	CreateAccessProfile(ctx context.Context, accessProfile *AccessProfile) (*AccessProfile, error)
+	UpdateAccessProfile(ctx context.Context, accessProfile []*UpdateAccessProfile, id interface{}) (*AccessProfile, error)
+	DeleteAccessProfile(ctx context.Context, accessProfile *AccessProfile) error
+}
+
+// RolesService manages roles and their dimensions
+type RolesService interface {
+	ListRoles(ctx context.Context, filters string) ([]*Role, error)
+	GetRole(ctx context.Context, id string) (*Role, error)
+	CreateRole(ctx context.Context, role *Role) (*Role, error)
+	UpdateRole(ctx context.Context, role []*UpdateRole, id interface{}) (*Role, error)
+	DeleteRole(ctx context.Context, role *Role) (*Role, error)
+
+	GetDimension(ctx context.Context, roleId string, dimensionId string) (*Dimension, error)
+	CreateDimension(ctx context.Context, roleId string, dimension *Dimension) (*Dimension, error)
+	UpdateDimension(ctx context.Context, roleId string, dimensionId string, patches []*UpdateDimension) (*Dimension, error)
+	DeleteDimension(ctx context.Context, roleId string, dimensionId string) error
+}
+
+// IdentitiesService looks up identities
+type IdentitiesService interface {
+	GetIdentityByAlias(ctx context.Context, alias string) ([]*Identity, error)
+	GetIdentityByEmail(ctx context.Context, email string) ([]*Identity, error)
+}
+
+// WorkgroupsService manages governance groups, called workgroups by the API, and their members
+type WorkgroupsService interface {
+	ListGovernanceGroups(ctx context.Context, filters string) ([]*GovernanceGroup, error)
+	GetGovernanceGroupByName(ctx context.Context, name string) ([]*GovernanceGroup, error)
+	GetGovernanceGroups(ctx context.Context, id string) (*GovernanceGroup, error)
+	CreateGovernanceGroup(ctx context.Context, governanceGroup *GovernanceGroup) (*GovernanceGroup, error)
+	UpdateGovernanceGroup(ctx context.Context, governanceGroup []*UpdateGovernanceGroup, id interface{}) (*GovernanceGroup, error)
+	DeleteGovernanceGroup(ctx context.Context, governanceGroup *GovernanceGroup) error
+
+	GetGovernanceGroupMembers(ctx context.Context, id string) (*GovernanceGroupMembers, error)
+	CreateGovernanceGroupMembers(ctx context.Context, governanceGroupMembers *GovernanceGroupMembers, id string) (*GovernanceGroupMembers, error)
+	UpdateGovernanceGroupMembers(ctx context.Context, governanceGroupMembers *GovernanceGroupMembers, governanceGroupMembersActual *GovernanceGroupMembers, id string) (*GovernanceGroupMembers, error)
+	DeleteGovernanceGroupMembers(ctx context.Context, governanceGroupMembers *GovernanceGroupMembers) error
+}
+
+// SourceAppsService manages source apps and the access profiles attached to them
+type SourceAppsService interface {
+	ListSourceApps(ctx context.Context, filters string) ([]*SourceApp, error)
+	GetSourceAppsAll(ctx context.Context) ([]*SourceApp, error)
+	GetSourceAppByName(ctx context.Context, name string) ([]*SourceApp, error)
+	GetSourceApp(ctx context.Context, id string) (*SourceApp, error)
+	CreateSourceApp(ctx context.Context, sourceApp *SourceApp) (*SourceApp, error)
+	UpdateSourceApp(ctx context.Context, sourceApp []*UpdateSourceApp, id interface{}) (*SourceApp, error)
+	DeleteSourceApp(ctx context.Context, sourceApp *SourceApp) error
+
+	GetAccessProfileAttachment(ctx context.Context, id string) (*AccessProfileAttachment, error)
+	UpdateAccessProfileAttachment(ctx context.Context, accessProfileAttachment *AccessProfileAttachment, id string) (*AccessProfileAttachment, error)
+	DeleteAccessProfileAttachment(ctx context.Context, accessProfileAttachment *AccessProfileAttachment) error
+}
+
+// PasswordPoliciesService manages password policies
+type PasswordPoliciesService interface {
+	GetPasswordPolicy(ctx context.Context, passwordPolicyId string) (*PasswordPolicy, error)
+	CreatePasswordPolicy(ctx context.Context, passwordPolicy *PasswordPolicy) (*PasswordPolicy, error)
+	UpdatePasswordPolicy(ctx context.Context, passwordPolicy *PasswordPolicy) (*PasswordPolicy, error)
+	DeletePasswordPolicy(ctx context.Context, passwordPolicyId string) error
+}
+
+// TaggedObjectsService manages the tags of objects
+type TaggedObjectsService interface {
+	GetTaggedObject(ctx context.Context, objectType string, objectID string) (*TaggedObject, error)
+	SetTaggedObject(ctx context.Context, taggedObject *TaggedObject) (*TaggedObject, error)
+	DeleteTaggedObject(ctx context.Context, objectType string, objectID string) error
+}
+
+// WorkflowsService manages workflows
+type WorkflowsService interface {
+	ListWorkflows(ctx context.Context, filters string) ([]*Workflow, error)
+	GetWorkflow(ctx context.Context, id string) (*Workflow, error)
+	GetWorkflowByName(ctx context.Context, name string) (*Workflow, error)
+	CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workflow, error)
+	UpdateWorkflow(ctx context.Context, id string, workflow *Workflow) (*Workflow, error)
+	DeleteWorkflow(ctx context.Context, id string) error
+}
+
+// TasksService follows asynchronous tasks, e.g. source deletions
+type TasksService interface {
+	GetTaskStatus(ctx context.Context, id string) (*TaskStatus, error)
+	WaitForTask(ctx context.Context, id string) (*TaskStatus, error)
+}
+
+// Services groups the API areas. Code that depends on these interfaces instead of *Client
+// can be tested against fakes of the areas it uses.
+type Services struct {
+	Sources          SourcesService
+	Entitlements     EntitlementsService
+	AccessProfiles   AccessProfilesService
+	Roles            RolesService
+	Identities       IdentitiesService
+	Workgroups       WorkgroupsService
+	SourceApps       SourceAppsService
+	PasswordPolicies PasswordPoliciesService
+	TaggedObjects    TaggedObjectsService
+	Workflows        WorkflowsService
+	Tasks            TasksService
+}
+
+// Services returns every API area served by c
+func (c *Client) Services() *Services {
+	return &Services{
+		Sources:          c,
+		Entitlements:     c,
+		AccessProfiles:   c,
+		Roles:            c,
+		Identities:       c,
+		Workgroups:       c,
+		SourceApps:       c,
+		PasswordPolicies: c,
+		TaggedObjects:    c,
+		Workflows:        c,
+		Tasks:            c,
+	}
+}
@@ -0,0 +1,130 @@
+package identitynow
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultTaskPollMin = 1 * time.Second
+	taskPollMax        = 15 * time.Second
+)
+
+// GetTaskStatus returns the status of an asynchronous task
+func (c *Client) GetTaskStatus(ctx context.Context, id string) (*TaskStatus, error) {
+	taskURL := c.apiURL("task-status", id)
+	tflog.Debug(ctx, "Creating HTTP request to get task status", map[string]interface{}{
+		"method":  "GET",
+		"url":     taskURL,
+		"task_id": id,
+	})
+	req, err := http.NewRequest("GET", taskURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+
+	req = req.WithContext(ctx)
+
+	res := TaskStatus{}
+	if err := c.sendRequest(ctx, req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// WaitForTask polls a task until it completes, backing off between polls.
+// It returns a TaskError when the task fails and stops when ctx is done.
+func (c *Client) WaitForTask(ctx context.Context, id string) (*TaskStatus, error) {
+	for attempt := 1; ; attempt++ {
+		status, err := c.GetTaskStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.CompletionStatus {
+		case "SUCCESS":
+			return status, nil
+		case "WARNING":
+			tflog.Warn(ctx, "Task completed with warnings", map[string]interface{}{
+				"task_id":  id,
+				"messages": taskMessages(status, ""),
+			})
+			return status, nil
+		case "ERROR", "TEMP_ERROR", "TERMINATED":
+			return status, &TaskError{
+				TaskID:           id,
+				Name:             status.UniqueName,
+				CompletionStatus: status.CompletionStatus,
+				Messages:         taskMessages(status, "ERROR"),
+			}
+		}
+
+		wait := c.pollDelay(attempt)
+		tflog.Debug(ctx, "Waiting for task to complete", map[string]interface{}{
+			"task_id":          id,
+			"percent_complete": status.PercentComplete,
+			"wait":             wait.String(),
+		})
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForDeletion polls get until it returns a NotFoundError, since some objects remain readable
+// for a while after their deletion was accepted
+func (c *Client) waitForDeletion(ctx context.Context, get func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := get(ctx)
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		wait := c.pollDelay(attempt)
+		tflog.Debug(ctx, "Waiting for deleted object to disappear", map[string]interface{}{
+			"wait": wait.String(),
+		})
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// pollDelay doubles the wait between polls, from the client's minimum up to taskPollMax
+func (c *Client) pollDelay(attempt int) time.Duration {
+	wait := c.taskPollMin
+	for i := 1; i < attempt && wait < taskPollMax; i++ {
+		wait *= 2
+	}
+	if wait > taskPollMax {
+		wait = taskPollMax
+	}
+	return wait
+}
+
+// taskMessages returns the message texts of a task, only those of the given type unless it is empty
+func taskMessages(status *TaskStatus, messageType string) []string {
+	var messages []string
+	for _, m := range status.Messages {
+		if m == nil || (messageType != "" && m.Type != messageType) {
+			continue
+		}
+		text := m.Key
+		if m.LocalizedText != nil && m.LocalizedText.Message != "" {
+			text = m.LocalizedText.Message
+		}
+		if text != "" {
+			messages = append(messages, text)
+		}
+	}
+	return messages
+}
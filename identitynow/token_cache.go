@@ -0,0 +1,117 @@
+package identitynow
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenCache persists access tokens across provider processes, so consecutive Terraform runs
+// against one tenant reuse a valid token instead of calling the token endpoint again.
+// Entries are encrypted with AES-GCM using a key taken from IDENTITYNOW_TOKEN_CACHE_KEY.
+type TokenCache struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+type tokenCacheEntry struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// NewTokenCache opens the cache in dir, the key can be any secret string
+func NewTokenCache(dir string, key string) (*TokenCache, error) {
+	if key == "" {
+		return nil, errors.New("IDENTITYNOW_TOKEN_CACHE_KEY must be set to encrypt the token cache")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating token cache directory: %w", err)
+	}
+
+	derived := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(derived[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenCache{dir: dir, aead: aead}, nil
+}
+
+// Load returns a cached token that stays valid for longer than the background refresh lead
+func (c *TokenCache) Load(baseURL string, clientID string, now time.Time) (string, time.Time, bool) {
+	data, err := os.ReadFile(c.path(baseURL, clientID))
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", time.Time{}, false
+	}
+
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, tokenCacheAAD(baseURL, clientID))
+	if err != nil {
+		// Written with another key or for another credential
+		return "", time.Time{}, false
+	}
+
+	var entry tokenCacheEntry
+	if err := json.Unmarshal(plain, &entry); err != nil || entry.AccessToken == "" {
+		return "", time.Time{}, false
+	}
+	if !now.Add(tokenBackgroundRefreshLead).Before(entry.Expiry) {
+		return "", time.Time{}, false
+	}
+	return entry.AccessToken, entry.Expiry, true
+}
+
+// Store saves a token with the expiry computed by tokenExpiry, so the safety margin is kept
+func (c *TokenCache) Store(baseURL string, clientID string, token string, expiry time.Time) error {
+	plain, err := json.Marshal(tokenCacheEntry{AccessToken: token, Expiry: expiry})
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data := c.aead.Seal(nonce, nonce, plain, tokenCacheAAD(baseURL, clientID))
+
+	// Write and rename, so concurrent Terraform runs never read a partial file
+	tmp, err := os.CreateTemp(c.dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(baseURL, clientID))
+}
+
+// Delete drops a cached token, e.g. after the API rejected it
+func (c *TokenCache) Delete(baseURL string, clientID string) {
+	os.Remove(c.path(baseURL, clientID))
+}
+
+func (c *TokenCache) path(baseURL string, clientID string) string {
+	sum := sha256.Sum256(tokenCacheAAD(baseURL, clientID))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".token")
+}
+
+// tokenCacheAAD binds an entry to its api_url and client_id
+func tokenCacheAAD(baseURL string, clientID string) []byte {
+	return []byte(baseURL + "\n" + clientID)
+}
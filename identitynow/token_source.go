@@ -0,0 +1,232 @@
+package identitynow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// tokenSafetyMargin is subtracted from expires_in so a token is never used right before it expires
+	tokenSafetyMargin = 5 * time.Minute
+	// tokenDefaultLifetime is used when the token response has no usable expires_in
+	tokenDefaultLifetime = 1 * time.Hour
+	// tokenBackgroundRefreshLead is how long before tokenExpiry the background refresh starts
+	tokenBackgroundRefreshLead = 1 * time.Minute
+)
+
+// tokenSource owns the access token of a single credential.
+// Concurrent callers share one in-flight refresh and the token is renewed in
+// the background shortly before it expires, so requests rarely wait for it.
+type tokenSource struct {
+	loggerCtx context.Context
+	fetch     func(ctx context.Context) (*OauthToken, error)
+
+	// Optional persistent cache shared with other provider processes
+	cache         *TokenCache
+	cacheBaseURL  string
+	cacheClientID string
+
+	mu       sync.Mutex
+	token    string
+	expiry   time.Time
+	rejected string
+	inflight *tokenRefresh
+	timer    *time.Timer
+}
+
+// tokenRefresh is a single in-flight token request shared by all waiting callers
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newTokenSource(loggerCtx context.Context, fetch func(ctx context.Context) (*OauthToken, error)) *tokenSource {
+	return &tokenSource{
+		loggerCtx: loggerCtx,
+		fetch:     fetch,
+	}
+}
+
+// Token returns a valid access token, fetching a new one if needed
+func (ts *tokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	if ts.token != "" && time.Now().Before(ts.expiry) {
+		token := ts.token
+		ts.mu.Unlock()
+		return token, nil
+	}
+	ts.mu.Unlock()
+
+	return ts.Refresh(ctx)
+}
+
+// Refresh fetches a new access token, joining a refresh that is already in flight
+func (ts *tokenSource) Refresh(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	call := ts.inflight
+	if call == nil {
+		call = &tokenRefresh{done: make(chan struct{})}
+		ts.inflight = call
+		// The refresh outlives the caller that started it, other callers may still be waiting for it
+		go ts.doRefresh(context.WithoutCancel(ctx), call)
+	}
+	ts.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-call.done:
+		return call.token, call.err
+	}
+}
+
+// Invalidate drops the cached token if it is still the given rejected token
+func (ts *tokenSource) Invalidate(rejected string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// Never load the rejected token from the persistent cache again
+	ts.rejected = rejected
+	if ts.token == rejected {
+		ts.token = ""
+		ts.expiry = time.Time{}
+		if ts.cache != nil {
+			ts.cache.Delete(ts.cacheBaseURL, ts.cacheClientID)
+		}
+	}
+}
+
+// usePersistentCache makes the source load and store tokens in cache under api_url and client_id
+func (ts *tokenSource) usePersistentCache(cache *TokenCache, baseURL string, clientID string) {
+	ts.cache = cache
+	ts.cacheBaseURL = baseURL
+	ts.cacheClientID = clientID
+}
+
+// Valid reports whether a cached token is present and not expired
+func (ts *tokenSource) Valid() bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return ts.token != "" && time.Now().Before(ts.expiry)
+}
+
+// Expiry returns when the cached token should no longer be used
+func (ts *tokenSource) Expiry() time.Time {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return ts.expiry
+}
+
+// Stop cancels the scheduled background refresh
+func (ts *tokenSource) Stop() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.timer != nil {
+		ts.timer.Stop()
+		ts.timer = nil
+	}
+}
+
+func (ts *tokenSource) doRefresh(ctx context.Context, call *tokenRefresh) {
+	ts.mu.Lock()
+	rejected := ts.rejected
+	ts.mu.Unlock()
+
+	token, expiry, err := ts.fetchToken(ctx, rejected)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.inflight = nil
+	if err == nil {
+		ts.token = token
+		ts.expiry = expiry
+		ts.scheduleRefresh()
+	}
+
+	call.token = ts.token
+	call.err = err
+	close(call.done)
+}
+
+// fetchToken returns a token from the persistent cache, or fetches a new one and caches it
+func (ts *tokenSource) fetchToken(ctx context.Context, rejected string) (string, time.Time, error) {
+	if ts.cache != nil {
+		if token, expiry, ok := ts.cache.Load(ts.cacheBaseURL, ts.cacheClientID, time.Now()); ok && token != rejected {
+			tflog.Debug(ctx, "Using OAuth token from persistent cache", map[string]interface{}{
+				"token_expiry": expiry.Format(time.RFC3339),
+			})
+			return token, expiry, nil
+		}
+	}
+
+	res, err := ts.fetch(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiry := tokenExpiry(ctx, res.ExpiresIn)
+
+	if ts.cache != nil {
+		if err := ts.cache.Store(ts.cacheBaseURL, ts.cacheClientID, res.AccessToken, expiry); err != nil {
+			tflog.Warn(ctx, "Failed to store OAuth token in persistent cache", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	return res.AccessToken, expiry, nil
+}
+
+// scheduleRefresh arms the background refresh, ts.mu must be held
+func (ts *tokenSource) scheduleRefresh() {
+	if ts.timer != nil {
+		ts.timer.Stop()
+	}
+
+	wait := time.Until(ts.expiry) - tokenBackgroundRefreshLead
+	if wait <= 0 {
+		ts.timer = nil
+		return
+	}
+
+	ts.timer = time.AfterFunc(wait, func() {
+		tflog.Debug(ts.loggerCtx, "Refreshing OAuth token in background")
+		if _, err := ts.Refresh(ts.loggerCtx); err != nil {
+			tflog.Warn(ts.loggerCtx, "Background OAuth token refresh failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	})
+}
+
+// tokenExpiry converts expires_in into the time the token should stop being used
+func tokenExpiry(ctx context.Context, expiresIn int) time.Time {
+	if expiresIn <= 0 {
+		// Fallback: set a default expiry of 1 hour if expires_in is missing or invalid
+		expiry := time.Now().Add(tokenDefaultLifetime)
+		tflog.Warn(ctx, "expires_in field missing or invalid, using default 1 hour expiry", map[string]interface{}{
+			"expires_in_received": expiresIn,
+			"default_expiry":      expiry.Format(time.RFC3339),
+		})
+		return expiry
+	}
+
+	expirationDuration := time.Duration(expiresIn) * time.Second
+	// Subtract the safety margin to refresh before actual expiry
+	if expirationDuration > tokenSafetyMargin {
+		expirationDuration -= tokenSafetyMargin
+	}
+	expiry := time.Now().Add(expirationDuration)
+
+	tflog.Debug(ctx, "Token expiry set", map[string]interface{}{
+		"expires_in_seconds": expiresIn,
+		"token_expiry":       expiry.Format(time.RFC3339),
+	})
+	return expiry
+}
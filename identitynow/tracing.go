@@ -0,0 +1,91 @@
+package identitynow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/plsph/terraform-provider-identitynow/identitynow"
+
+// tracer returns the tracer of the globally registered provider, API calls become children
+// of whatever span the caller's context carries
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// requestSpanStats collects what happened while sending one API request
+type requestSpanStats struct {
+	retries     int
+	limiterWait time.Duration
+}
+
+// startRequestSpan starts the client span of an API call. Object ids are replaced in the span name,
+// so calls to the same endpoint group together.
+func startRequestSpan(ctx context.Context, req *http.Request) (context.Context, trace.Span) {
+	template := urlTemplate(req.URL.Path)
+	return tracer().Start(ctx, req.Method+" "+template,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.template", template),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+}
+
+// endRequestSpan records the outcome of an API call
+func endRequestSpan(span trace.Span, res *http.Response, stats requestSpanStats, err error) {
+	span.SetAttributes(
+		attribute.Int("http.request.resend_count", stats.retries),
+		attribute.Int64("identitynow.rate_limiter.wait_ms", stats.limiterWait.Milliseconds()),
+	)
+	if res != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	}
+
+	trackingID := ""
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		trackingID = apiErr.TrackingID
+	case res != nil:
+		trackingID = res.Header.Get("SLPT-Request-ID")
+	}
+	if trackingID != "" {
+		span.SetAttributes(attribute.String("identitynow.tracking_id", trackingID))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// urlTemplate replaces the id segments of an API path with {id}, e.g.
+// /v2025/roles/2c91808a7813090a017814121e121518/dimensions becomes /v2025/roles/{id}/dimensions
+func urlTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isIDSegment(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIDSegment(segment string) bool {
+	if len(segment) < 8 || apiVersionPattern.MatchString(segment) {
+		return false
+	}
+	return strings.IndexFunc(segment, unicode.IsDigit) >= 0
+}
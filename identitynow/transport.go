@@ -0,0 +1,100 @@
+package identitynow
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultHTTPTimeout bounds every request made by a client, including the token request
+const DefaultHTTPTimeout = time.Minute
+
+// TransportSettings holds the network settings of the provider configuration
+type TransportSettings struct {
+	HTTPSProxy         string
+	CACertPEM          string
+	CACertFile         string
+	ClientCertPEM      string
+	ClientKeyPEM       string
+	ClientCertFile     string
+	ClientKeyFile      string
+	InsecureSkipVerify bool
+}
+
+// NewHTTPTransport builds the transport shared by every pooled client.
+// Without https_proxy it keeps honoring the HTTPS_PROXY and NO_PROXY environment variables.
+func NewHTTPTransport(settings TransportSettings) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if settings.HTTPSProxy != "" {
+		proxyURL, err := url.Parse(settings.HTTPSProxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("https_proxy must be an absolute URL such as http://proxy.example.com:3128, got %q", settings.HTTPSProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+	}
+
+	caPEM, err := pemSetting("ca_cert", settings.CACertPEM, settings.CACertFile)
+	if err != nil {
+		return nil, err
+	}
+	if caPEM != nil {
+		// Private CAs are trusted in addition to the system roots, so direct connections keep working
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("ca_cert does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPEM, err := pemSetting("client_cert", settings.ClientCertPEM, settings.ClientCertFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pemSetting("client_key", settings.ClientKeyPEM, settings.ClientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if (certPEM == nil) != (keyPEM == nil) {
+		return nil, fmt.Errorf("a client certificate and its key must be configured together")
+	}
+	if certPEM != nil {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// pemSetting returns PEM data given inline as <name>_pem or read from <name>_file
+func pemSetting(name string, inline string, file string) ([]byte, error) {
+	switch {
+	case inline != "" && file != "":
+		return nil, fmt.Errorf("only one of %s_pem and %s_file can be set", name, name)
+	case inline != "":
+		return []byte(inline), nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s_file: %w", name, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type Identity struct {
 	ID                 string              `json:"id,omitempty"`
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type AccessProfile struct {
 	Description         string                   `json:"description"`
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type AccessProfileAttachment struct {
 	AccessProfiles      []string                 `json:"accessProfiles,omitempty"`
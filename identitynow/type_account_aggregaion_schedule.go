@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type AccountAggregationSchedule struct {
 	Arguments struct {
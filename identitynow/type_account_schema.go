@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type AccountSchema struct {
 	Attributes         []*AccountSchemaAttribute `json:"attributes,omitempty"`
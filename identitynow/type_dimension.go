@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type Dimension struct {
 	Description    string          `json:"description"`
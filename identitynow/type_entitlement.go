@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type SourceEntitlement struct {
 	Attribute              string        `json:"attribute,omitempty"`
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type GovernanceGroup struct {
 	Description          string                `json:"description"`
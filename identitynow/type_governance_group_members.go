@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type GovernanceGroupMembers struct {
 	GovernanceGroupId        string                   `json:"id,omitempty"`
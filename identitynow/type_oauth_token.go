@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type OauthToken struct {
 	AccessToken         string `json:"access_token"`
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type PasswordPolicy struct {
 	AccountIDMinWordLength                *int                 `json:"accountIdMinWordLength,omitempty"`
@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type Role struct {
 	Description             string                   `json:"description"`
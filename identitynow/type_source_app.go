@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type SourceApp struct {
 	Description         string                   `json:"description"`
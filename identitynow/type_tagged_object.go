@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type TaggedObject struct {
 	ObjectRef *TaggedObjectRef `json:"objectRef"`
@@ -0,0 +1,31 @@
+package identitynow
+
+// TaskResult references the task running an asynchronous operation, e.g. the response of a source deletion
+type TaskResult struct {
+	Type string `json:"type,omitempty"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type TaskStatus struct {
+	ID               string               `json:"id"`
+	Type             string               `json:"type,omitempty"`
+	UniqueName       string               `json:"uniqueName,omitempty"`
+	Description      string               `json:"description,omitempty"`
+	Launched         string               `json:"launched,omitempty"`
+	Completed        string               `json:"completed,omitempty"`
+	CompletionStatus string               `json:"completionStatus,omitempty"`
+	PercentComplete  int                  `json:"percentComplete,omitempty"`
+	Messages         []*TaskStatusMessage `json:"messages,omitempty"`
+}
+
+type TaskStatusMessage struct {
+	Type          string         `json:"type"`
+	Key           string         `json:"key,omitempty"`
+	LocalizedText *LocalizedText `json:"localizedText,omitempty"`
+}
+
+type LocalizedText struct {
+	Locale  string `json:"locale"`
+	Message string `json:"message"`
+}
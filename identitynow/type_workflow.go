@@ -1,4 +1,4 @@
-package main
+package identitynow
 
 type Workflow struct {
 	ID             string              `json:"id,omitempty"`
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
+)
+
+// importNamePrefix marks import IDs that are names, e.g. `terraform import identitynow_role.admins name:Admins`
+const importNamePrefix = "name:"
+
+// importIDByName resolves a `name:<value>` import ID to the id of the only object of the type with that name.
+// Other import IDs are returned unchanged.
+func (cfg *Config) importIDByName(ctx context.Context, importID string, listing listableType, diags *diag.Diagnostics) string {
+	name, ok := strings.CutPrefix(importID, importNamePrefix)
+	if !ok {
+		return importID
+	}
+	objectType := strings.ReplaceAll(listing.name, "_", " ")
+	if name == "" {
+		diags.AddError("Invalid Import ID", fmt.Sprintf("Expected name:<%s name>, got: %s", objectType, importID))
+		return ""
+	}
+
+	services, err := cfg.apiServices(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
+		return ""
+	}
+	objects, err := listing.byName(ctx, services, name)
+	if err != nil && !identitynow.IsNotFound(err) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to look up %s %q: %s", objectType, name, err))
+		return ""
+	}
+
+	switch len(objects) {
+	case 0:
+		diags.AddError("Import Name Not Found", fmt.Sprintf("No %s is named %q.", objectType, name))
+	case 1:
+		return objects[0].id
+	default:
+		ids := make([]string, len(objects))
+		for i, object := range objects {
+			ids[i] = object.id
+		}
+		diags.AddError(
+			"Ambiguous Import Name",
+			fmt.Sprintf("%d objects of type %s are named %q: %s. Import the one you want by its id.", len(objects), objectType, name, strings.Join(ids, ", ")),
+		)
+	}
+	return ""
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
+)
+
+// listedObject is an object returned by listing a resource type
+type listedObject struct {
+	id   string
+	name string
+}
+
+// listableType is a resource type whose objects can be listed from a tenant
+type listableType struct {
+	name        string
+	newResource func() resource.Resource
+	list        func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error)
+	// byName returns every object with the name, for `name:<value>` import IDs
+	byName func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error)
+}
+
+var governanceGroupListing = listableType{
+	name:        "governance_group",
+	newResource: NewGovernanceGroupResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		groups, err := services.Workgroups.ListGovernanceGroups(ctx, filters)
+		objects := make([]listedObject, 0, len(groups))
+		for _, group := range groups {
+			objects = append(objects, listedObject{id: group.ID, name: group.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		groups, err := services.Workgroups.GetGovernanceGroupByName(ctx, name)
+		objects := make([]listedObject, 0, len(groups))
+		for _, group := range groups {
+			objects = append(objects, listedObject{id: group.ID, name: group.Name})
+		}
+		return objects, err
+	},
+}
+
+var sourceListing = listableType{
+	name:        "source",
+	newResource: NewSourceResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		sources, err := services.Sources.ListSources(ctx, filters)
+		objects := make([]listedObject, 0, len(sources))
+		for _, source := range sources {
+			objects = append(objects, listedObject{id: source.ID, name: source.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		sources, err := services.Sources.GetSourceByName(ctx, name)
+		objects := make([]listedObject, 0, len(sources))
+		for _, source := range sources {
+			objects = append(objects, listedObject{id: source.ID, name: source.Name})
+		}
+		return objects, err
+	},
+}
+
+var accessProfileListing = listableType{
+	name:        "access_profile",
+	newResource: NewAccessProfileResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		profiles, err := services.AccessProfiles.ListAccessProfiles(ctx, filters)
+		objects := make([]listedObject, 0, len(profiles))
+		for _, profile := range profiles {
+			objects = append(objects, listedObject{id: profile.ID, name: profile.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		profiles, err := services.AccessProfiles.GetAccessProfileByName(ctx, name)
+		objects := make([]listedObject, 0, len(profiles))
+		for _, profile := range profiles {
+			objects = append(objects, listedObject{id: profile.ID, name: profile.Name})
+		}
+		return objects, err
+	},
+}
+
+var sourceAppListing = listableType{
+	name:        "source_app",
+	newResource: NewSourceAppResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		apps, err := services.SourceApps.ListSourceApps(ctx, filters)
+		objects := make([]listedObject, 0, len(apps))
+		for _, app := range apps {
+			objects = append(objects, listedObject{id: app.ID, name: app.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		apps, err := services.SourceApps.GetSourceAppByName(ctx, name)
+		objects := make([]listedObject, 0, len(apps))
+		for _, app := range apps {
+			objects = append(objects, listedObject{id: app.ID, name: app.Name})
+		}
+		return objects, err
+	},
+}
+
+var roleListing = listableType{
+	name:        "role",
+	newResource: NewRoleResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		roles, err := services.Roles.ListRoles(ctx, filters)
+		objects := make([]listedObject, 0, len(roles))
+		for _, role := range roles {
+			objects = append(objects, listedObject{id: role.ID, name: role.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		roles, err := services.Roles.ListRoles(ctx, "name eq "+identitynow.FilterString(name))
+		objects := make([]listedObject, 0, len(roles))
+		for _, role := range roles {
+			objects = append(objects, listedObject{id: role.ID, name: role.Name})
+		}
+		return objects, err
+	},
+}
+
+var workflowListing = listableType{
+	name:        "workflow",
+	newResource: NewWorkflowResource,
+	list: func(ctx context.Context, services *identitynow.Services, filters string) ([]listedObject, error) {
+		workflows, err := services.Workflows.ListWorkflows(ctx, filters)
+		objects := make([]listedObject, 0, len(workflows))
+		for _, workflow := range workflows {
+			objects = append(objects, listedObject{id: workflow.ID, name: workflow.Name})
+		}
+		return objects, err
+	},
+	byName: func(ctx context.Context, services *identitynow.Services, name string) ([]listedObject, error) {
+		// GetWorkflowByName stops at the first match, so duplicates are found by listing every workflow
+		workflows, err := services.Workflows.ListWorkflows(ctx, "")
+		var objects []listedObject
+		for _, workflow := range workflows {
+			if workflow.Name == name {
+				objects = append(objects, listedObject{id: workflow.ID, name: workflow.Name})
+			}
+		}
+		return objects, err
+	},
+}
+
+// listableTypes are ordered so that referenced objects come before the objects referencing them
+var listableTypes = []listableType{
+	governanceGroupListing,
+	sourceListing,
+	accessProfileListing,
+	sourceAppListing,
+	roleListing,
+	workflowListing,
+}
+
+var _ list.ListResourceWithConfigure = &ListResource{}
+
+func NewSourceListResource() list.ListResource {
+	return &ListResource{listing: sourceListing}
+}
+
+func NewAccessProfileListResource() list.ListResource {
+	return &ListResource{listing: accessProfileListing}
+}
+
+func NewRoleListResource() list.ListResource {
+	return &ListResource{listing: roleListing}
+}
+
+func NewGovernanceGroupListResource() list.ListResource {
+	return &ListResource{listing: governanceGroupListing}
+}
+
+func NewWorkflowListResource() list.ListResource {
+	return &ListResource{listing: workflowListing}
+}
+
+// ListResource lists the objects of one resource type for `terraform query`
+type ListResource struct {
+	listing listableType
+	client  *Config
+}
+
+// ListResourceModel describes the list block arguments
+type ListResourceModel struct {
+	Filters    types.String `tfsdk:"filters"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+}
+
+func (r *ListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.listing.name
+}
+
+func (r *ListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Description: fmt.Sprintf("Lists the %ss of the tenant.", strings.ReplaceAll(r.listing.name, "_", " ")),
+		Attributes: map[string]listschema.Attribute{
+			"filters": listschema.StringAttribute{
+				Description: "Filter expression in the API filters syntax, e.g. `name co \"Admin\"`.",
+				Optional:    true,
+			},
+			"name_prefix": listschema.StringAttribute{
+				Description: "Only list objects whose name starts with this value. Combined with filters using and.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *ListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*Config)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected List Resource Configure Type", fmt.Sprintf("Expected *Config, got: %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *ListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var diags diag.Diagnostics
+	ctx, span := startOperationSpan(ctx, "identitynow_"+r.listing.name, "list")
+	defer endOperationSpan(span, &diags)
+
+	var data ListResourceModel
+	diags.Append(req.Config.Get(ctx, &data)...)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	services, err := r.client.apiServices(ctx)
+	if err != nil {
+		diags.AddError("Client Error", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	objects, err := r.listing.list(ctx, services, listFilters(data))
+	if err != nil {
+		diags.AddError("Client Error", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	// Full objects are read through the managed resource, so they match what an import would store
+	res := r.listing.newResource()
+	res.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: r.client}, &resource.ConfigureResponse{})
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, object := range objects {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = object.name
+			setIDIdentity(ctx, result.Identity, types.StringValue(object.id), &result.Diagnostics)
+			if req.IncludeResource {
+				state, readDiags := readResourceByID(ctx, res, object.id)
+				result.Diagnostics.Append(readDiags...)
+				if state.IsNull() && !readDiags.HasError() {
+					// Deleted since it was listed
+					continue
+				}
+				result.Resource.Raw = state
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+// listFilters combines the list arguments into one filters expression
+func listFilters(data ListResourceModel) string {
+	var filters []string
+	if prefix := data.NamePrefix.ValueString(); prefix != "" {
+		filters = append(filters, "name sw "+identitynow.FilterString(prefix))
+	}
+	if expression := data.Filters.ValueString(); expression != "" {
+		if len(filters) > 0 {
+			expression = "(" + expression + ")"
+		}
+		filters = append(filters, expression)
+	}
+	return strings.Join(filters, " and ")
+}
+
+// readResourceByID reads an object the same way `terraform import` would, starting from a state holding only its id
+func readResourceByID(ctx context.Context, res resource.Resource, id string) (tftypes.Value, diag.Diagnostics) {
+	var schemaResp resource.SchemaResponse
+	res.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range schemaType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	attributes["id"] = tftypes.NewValue(tftypes.String, id)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaType, attributes)}
+
+	resp := resource.ReadResponse{State: state}
+	res.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	return resp.State.Raw, resp.Diagnostics
+}
@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 const (
@@ -19,6 +26,8 @@ const (
 
 // Ensure IdentityNowProvider implements provider.Provider
 var _ provider.Provider = &IdentityNowProvider{}
+var _ provider.ProviderWithListResources = &IdentityNowProvider{}
+var _ provider.ProviderWithFunctions = &IdentityNowProvider{}
 
 // IdentityNowProvider defines the provider implementation
 type IdentityNowProvider struct {
@@ -34,6 +43,28 @@ type IdentityNowProviderModel struct {
 	MaxClientPoolSize      types.Int64  `tfsdk:"max_client_pool_size"`
 	DefaultClientPoolSize  types.Int64  `tfsdk:"default_client_pool_size"`
 	ClientRequestRateLimit types.Int64  `tfsdk:"client_request_rate_limit"`
+	MaxRetries             types.Int64  `tfsdk:"max_retries"`
+	ApiVersion             types.String `tfsdk:"api_version"`
+	ApiVersionOverrides    types.Map    `tfsdk:"api_version_overrides"`
+	HttpsProxy             types.String `tfsdk:"https_proxy"`
+	CaCertPem              types.String `tfsdk:"ca_cert_pem"`
+	CaCertFile             types.String `tfsdk:"ca_cert_file"`
+	ClientCertPem          types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPem           types.String `tfsdk:"client_key_pem"`
+	ClientCertFile         types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile          types.String `tfsdk:"client_key_file"`
+	InsecureSkipVerify     types.Bool   `tfsdk:"insecure_skip_verify"`
+	HttpTimeout            types.Int64  `tfsdk:"http_timeout"`
+	AccessToken            types.String `tfsdk:"access_token"`
+	TokenFile              types.String `tfsdk:"token_file"`
+	PatId                  types.String `tfsdk:"personal_access_token_id"`
+	PatSecret              types.String `tfsdk:"personal_access_token_secret"`
+	TokenCacheDir          types.String `tfsdk:"token_cache_dir"`
+	AuditLogPath           types.String `tfsdk:"audit_log_path"`
+	ReadOnly               types.Bool   `tfsdk:"read_only"`
+	RedactionKeys          types.List   `tfsdk:"redaction_keys"`
+	ReadCacheTtl           types.Int64  `tfsdk:"read_cache_ttl"`
+	ReadCacheMaxEntries    types.Int64  `tfsdk:"read_cache_max_entries"`
 }
 
 // CredentialModel describes a single credential
@@ -105,6 +136,100 @@ func (p *IdentityNowProvider) Schema(ctx context.Context, req provider.SchemaReq
 				Description: "Client request rate limit for communication with the IdentityNow API",
 				Optional:    true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Maximum number of retries for requests rejected with 429, 502, 503 or 504 by the IdentityNow API",
+				Optional:    true,
+			},
+			"api_version": schema.StringAttribute{
+				Description: "IdentityNow API version used in request paths, e.g. v2025, v2026 or beta. Defaults to v2025",
+				Optional:    true,
+			},
+			"api_version_overrides": schema.MapAttribute{
+				Description: "API version per API resource path, e.g. { workflows = \"beta\" }, overriding api_version for that resource",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"https_proxy": schema.StringAttribute{
+				Description: "Proxy URL for requests to the IdentityNow API. Can also be set with the IDENTITYNOW_HTTPS_PROXY environment variable. When unset, the standard HTTPS_PROXY and NO_PROXY environment variables apply",
+				Optional:    true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded CA certificates trusted in addition to the system roots, e.g. for a TLS-inspecting proxy",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM file with CA certificates trusted in addition to the system roots",
+				Optional:    true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				Description: "PEM encoded client certificate for mutual TLS",
+				Optional:    true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				Description: "PEM encoded private key of the client certificate",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM file with the client certificate for mutual TLS",
+				Optional:    true,
+			},
+			"client_key_file": schema.StringAttribute{
+				Description: "Path to a PEM file with the private key of the client certificate",
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Disable TLS certificate verification. Only meant for debugging, never use it in production",
+				Optional:    true,
+			},
+			"http_timeout": schema.Int64Attribute{
+				Description: "Timeout in seconds for a single HTTP request to the IdentityNow API. Defaults to 60",
+				Optional:    true,
+			},
+			"read_cache_ttl": schema.Int64Attribute{
+				Description: "Seconds that identity and entitlement lookups of data sources are cached for. Defaults to 300, 0 disables the cache",
+				Optional:    true,
+			},
+			"read_cache_max_entries": schema.Int64Attribute{
+				Description: "Maximum number of cached lookups, the least recently used are evicted first. Defaults to 1000",
+				Optional:    true,
+			},
+			"access_token": schema.StringAttribute{
+				Description: "Pre-issued access token used instead of client credentials",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_file": schema.StringAttribute{
+				Description: "Path to a file holding an access token, re-read whenever the token is rotated by another process",
+				Optional:    true,
+			},
+			"personal_access_token_id": schema.StringAttribute{
+				Description: "Client ID of a personal access token used instead of an API client",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"personal_access_token_secret": schema.StringAttribute{
+				Description: "Secret of the personal access token",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_cache_dir": schema.StringAttribute{
+				Description: "Directory of an encrypted cache that shares access tokens between Terraform runs. The encryption key is read from IDENTITYNOW_TOKEN_CACHE_KEY",
+				Optional:    true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Refuse every request that would change the tenant, so only plans, data sources and imports work. May also be provided via IDENTITYNOW_READ_ONLY environment variable.",
+				Optional:    true,
+			},
+			"redaction_keys": schema.ListAttribute{
+				Description: "Additional keys, e.g. apiToken, or JSON paths, e.g. $.connectorAttributes.domainSettings.*, whose values are redacted from TRACE logs and the audit log",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"audit_log_path": schema.StringAttribute{
+				Description: "File that every POST, PUT, PATCH and DELETE sent to the IdentityNow API is appended to as a JSON line. May also be provided via IDENTITYNOW_AUDIT_LOG_PATH environment variable.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -121,6 +246,64 @@ func (p *IdentityNowProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
+	// Only one authentication mode can be configured, attributes take precedence over environment variables
+	var authModes []string
+	if !data.AccessToken.IsNull() {
+		authModes = append(authModes, "access_token")
+	}
+	if !data.TokenFile.IsNull() {
+		authModes = append(authModes, "token_file")
+	}
+	if !data.PatId.IsNull() || !data.PatSecret.IsNull() {
+		authModes = append(authModes, "personal_access_token_id/personal_access_token_secret")
+	}
+	if !data.ClientId.IsNull() || !data.ClientSecret.IsNull() || !data.Credentials.IsNull() {
+		authModes = append(authModes, "client_id/client_secret/credentials")
+	}
+	if len(authModes) > 1 {
+		resp.Diagnostics.AddError(
+			"Conflicting IdentityNow authentication settings",
+			fmt.Sprintf("Only one authentication mode can be configured, got: %s.", strings.Join(authModes, ", ")),
+		)
+		return
+	}
+	if len(authModes) == 0 {
+		if v := os.Getenv("IDENTITYNOW_ACCESS_TOKEN"); v != "" {
+			data.AccessToken = types.StringValue(v)
+		} else if v := os.Getenv("IDENTITYNOW_TOKEN_FILE"); v != "" {
+			data.TokenFile = types.StringValue(v)
+		} else if os.Getenv("IDENTITYNOW_PAT_ID") != "" || os.Getenv("IDENTITYNOW_PAT_SECRET") != "" {
+			data.PatId = types.StringValue(os.Getenv("IDENTITYNOW_PAT_ID"))
+			data.PatSecret = types.StringValue(os.Getenv("IDENTITYNOW_PAT_SECRET"))
+		}
+	}
+
+	// Personal access tokens use the client credentials grant with the token id and secret
+	if !data.PatId.IsNull() || !data.PatSecret.IsNull() {
+		if data.PatId.ValueString() == "" || data.PatSecret.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("personal_access_token_id"),
+				"Incomplete IdentityNow personal access token",
+				"Both personal_access_token_id and personal_access_token_secret must be set, "+
+					"or the IDENTITYNOW_PAT_ID and IDENTITYNOW_PAT_SECRET environment variables.",
+			)
+			return
+		}
+		data.ClientId = data.PatId
+		data.ClientSecret = data.PatSecret
+	}
+
+	if !data.TokenFile.IsNull() {
+		if _, err := os.Stat(data.TokenFile.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_file"),
+				"Invalid IdentityNow token file",
+				fmt.Sprintf("The token_file cannot be read: %s.", err),
+			)
+			return
+		}
+	}
+
 	// Set default values from environment variables
 	if data.ApiUrl.IsNull() {
 		apiUrl := os.Getenv("IDENTITYNOW_URL")
@@ -167,6 +350,145 @@ func (p *IdentityNowProvider) Configure(ctx context.Context, req provider.Config
 		}
 	}
 
+	if data.MaxRetries.IsNull() {
+		maxRetries := int64(identitynow.DefaultMaxRetries)
+		if v := os.Getenv("IDENTITYNOW_MAX_RETRIES"); v != "" {
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("max_retries"),
+					"Invalid IdentityNow max retries",
+					fmt.Sprintf("The IDENTITYNOW_MAX_RETRIES environment variable must be an integer, got %q.", v),
+				)
+				return
+			}
+			maxRetries = parsed
+		}
+		data.MaxRetries = types.Int64Value(maxRetries)
+	}
+
+	if data.MaxRetries.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid IdentityNow max retries",
+			"The max_retries value must not be negative.",
+		)
+	}
+
+	if data.ApiVersion.IsNull() {
+		apiVersion := os.Getenv("IDENTITYNOW_API_VERSION")
+		if apiVersion == "" {
+			apiVersion = identitynow.DefaultAPIVersion
+		}
+		data.ApiVersion = types.StringValue(apiVersion)
+	}
+
+	apiVersionOverrides := map[string]string{}
+	if !data.ApiVersionOverrides.IsNull() {
+		resp.Diagnostics.Append(data.ApiVersionOverrides.ElementsAs(ctx, &apiVersionOverrides, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if err := identitynow.ValidateAPIVersions(data.ApiVersion.ValueString(), apiVersionOverrides); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_version"),
+			"Invalid IdentityNow API version",
+			err.Error(),
+		)
+	}
+
+	if data.HttpsProxy.IsNull() {
+		data.HttpsProxy = types.StringValue(os.Getenv("IDENTITYNOW_HTTPS_PROXY"))
+	}
+
+	if data.CaCertFile.IsNull() && data.CaCertPem.IsNull() {
+		data.CaCertFile = types.StringValue(os.Getenv("IDENTITYNOW_CA_CERT_FILE"))
+	}
+
+	if data.HttpTimeout.IsNull() {
+		httpTimeout := int64(identitynow.DefaultHTTPTimeout / time.Second)
+		if v := os.Getenv("IDENTITYNOW_HTTP_TIMEOUT"); v != "" {
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("http_timeout"),
+					"Invalid IdentityNow HTTP timeout",
+					fmt.Sprintf("The IDENTITYNOW_HTTP_TIMEOUT environment variable must be a number of seconds, got %q.", v),
+				)
+				return
+			}
+			httpTimeout = parsed
+		}
+		data.HttpTimeout = types.Int64Value(httpTimeout)
+	}
+
+	if data.HttpTimeout.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("http_timeout"),
+			"Invalid IdentityNow HTTP timeout",
+			"The http_timeout value must be a positive number of seconds.",
+		)
+	}
+
+	if data.ReadCacheTtl.IsNull() {
+		data.ReadCacheTtl = types.Int64Value(int64(identitynow.DefaultReadCacheTTL / time.Second))
+	}
+	if data.ReadCacheTtl.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_cache_ttl"),
+			"Invalid IdentityNow read cache TTL",
+			"The read_cache_ttl value must not be negative.",
+		)
+	}
+
+	if data.ReadCacheMaxEntries.IsNull() {
+		data.ReadCacheMaxEntries = types.Int64Value(identitynow.DefaultReadCacheMaxEntries)
+	}
+	if data.ReadCacheMaxEntries.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_cache_max_entries"),
+			"Invalid IdentityNow read cache size",
+			"The read_cache_max_entries value must not be negative.",
+		)
+	}
+
+	if data.InsecureSkipVerify.ValueBool() {
+		tflog.Warn(ctx, "TLS certificate verification is disabled for the IdentityNow API")
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS certificate verification is disabled",
+			"insecure_skip_verify is set, so the provider accepts any certificate presented for the IdentityNow API. "+
+				"Client secrets and access tokens can be intercepted by anyone on the network path. Only use this for debugging.",
+		)
+	}
+
+	if data.TokenCacheDir.IsNull() {
+		data.TokenCacheDir = types.StringValue(os.Getenv("IDENTITYNOW_TOKEN_CACHE_DIR"))
+	}
+
+	if data.ReadOnly.IsNull() {
+		readOnly := false
+		if v := os.Getenv("IDENTITYNOW_READ_ONLY"); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("read_only"),
+					"Invalid IdentityNow read-only setting",
+					fmt.Sprintf("The IDENTITYNOW_READ_ONLY environment variable must be a boolean, got %q.", v),
+				)
+				return
+			}
+			readOnly = parsed
+		}
+		data.ReadOnly = types.BoolValue(readOnly)
+	}
+
+	if data.AuditLogPath.IsNull() {
+		data.AuditLogPath = types.StringValue(os.Getenv("IDENTITYNOW_AUDIT_LOG_PATH"))
+	}
+
 	// Validate required fields
 	if data.ApiUrl.ValueString() == providerDefaultEmptyString {
 		resp.Diagnostics.AddAttributeError(
@@ -182,7 +504,7 @@ func (p *IdentityNowProvider) Configure(ctx context.Context, req provider.Config
 	}
 
 	// Parse credentials
-	credentials := []ClientCredential{}
+	credentials := []identitynow.ClientCredential{}
 	if !data.Credentials.IsNull() && len(data.Credentials.Elements()) > 0 {
 		var credsList []CredentialModel
 		resp.Diagnostics.Append(data.Credentials.ElementsAs(ctx, &credsList, false)...)
@@ -191,42 +513,157 @@ func (p *IdentityNowProvider) Configure(ctx context.Context, req provider.Config
 		}
 
 		for _, cred := range credsList {
-			credentials = append(credentials, ClientCredential{
+			credentials = append(credentials, identitynow.ClientCredential{
 				ClientId:     cred.ClientId.ValueString(),
 				ClientSecret: cred.ClientSecret.ValueString(),
 			})
 		}
+	} else if !data.AccessToken.IsNull() || !data.TokenFile.IsNull() {
+		// Token modes have a single credential without client id and secret
+		credentials = []identitynow.ClientCredential{{}}
 	} else {
-		credentials = []ClientCredential{{
+		credentials = []identitynow.ClientCredential{{
 			ClientId:     data.ClientId.ValueString(),
 			ClientSecret: data.ClientSecret.ValueString(),
 		}}
 	}
 
+	var redactionKeys []string
+	if !data.RedactionKeys.IsNull() {
+		resp.Diagnostics.Append(data.RedactionKeys.ElementsAs(ctx, &redactionKeys, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	redactor, err := identitynow.NewRedactor(redactionKeys)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("redaction_keys"),
+			"Invalid IdentityNow redaction key",
+			err.Error(),
+		)
+		return
+	}
+
 	tflog.Debug(ctx, "Provider configuration", map[string]interface{}{
 		"api_url":                   data.ApiUrl.ValueString(),
+		"auth_mode":                 authModeName(data),
 		"credentials_pool_size":     len(credentials),
 		"max_client_pool_size":      data.MaxClientPoolSize.ValueInt64(),
 		"default_client_pool_size":  data.DefaultClientPoolSize.ValueInt64(),
 		"client_request_rate_limit": data.ClientRequestRateLimit.ValueInt64(),
+		"max_retries":               data.MaxRetries.ValueInt64(),
+		"api_version":               data.ApiVersion.ValueString(),
+		"api_version_overrides":     apiVersionOverrides,
+		"https_proxy":               data.HttpsProxy.ValueString(),
+		"ca_cert_file":              data.CaCertFile.ValueString(),
+		"client_cert_file":          data.ClientCertFile.ValueString(),
+		"insecure_skip_verify":      data.InsecureSkipVerify.ValueBool(),
+		"http_timeout":              data.HttpTimeout.ValueInt64(),
+		"read_cache_ttl":            data.ReadCacheTtl.ValueInt64(),
+		"read_cache_max_entries":    data.ReadCacheMaxEntries.ValueInt64(),
+		"token_cache_dir":           data.TokenCacheDir.ValueString(),
+		"audit_log_path":            data.AuditLogPath.ValueString(),
+		"read_only":                 data.ReadOnly.ValueBool(),
+		"redaction_keys":            redactionKeys,
 	})
 
+	var cache *identitynow.TokenCache
+	if dir := data.TokenCacheDir.ValueString(); dir != "" {
+		var err error
+		cache, err = identitynow.NewTokenCache(dir, os.Getenv("IDENTITYNOW_TOKEN_CACHE_KEY"))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_cache_dir"),
+				"Invalid IdentityNow token cache",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	var audit *identitynow.AuditLog
+	if auditPath := data.AuditLogPath.ValueString(); auditPath != "" {
+		audit, err = identitynow.OpenAuditLog(auditPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("audit_log_path"),
+				"Invalid IdentityNow audit log",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	httpTransport, err := identitynow.NewHTTPTransport(identitynow.TransportSettings{
+		HTTPSProxy:         data.HttpsProxy.ValueString(),
+		CACertPEM:          data.CaCertPem.ValueString(),
+		CACertFile:         data.CaCertFile.ValueString(),
+		ClientCertPEM:      data.ClientCertPem.ValueString(),
+		ClientKeyPEM:       data.ClientKeyPem.ValueString(),
+		ClientCertFile:     data.ClientCertFile.ValueString(),
+		ClientKeyFile:      data.ClientKeyFile.ValueString(),
+		InsecureSkipVerify: data.InsecureSkipVerify.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid IdentityNow transport configuration", err.Error())
+		return
+	}
+
+	// Record or replay API traffic when IDENTITYNOW_CASSETTE_MODE is set
+	transport, err := identitynow.CassetteTransportFromEnv(httpTransport, redactor)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid IdentityNow cassette configuration", err.Error())
+		return
+	}
+
 	config := &Config{
-		URL:                    data.ApiUrl.ValueString(),
-		ClientId:               data.ClientId.ValueString(),
-		ClientSecret:           data.ClientSecret.ValueString(),
-		Credentials:            credentials,
-		MaxClientPoolSize:      int(data.MaxClientPoolSize.ValueInt64()),
-		DefaultClientPoolSize:  int(data.DefaultClientPoolSize.ValueInt64()),
-		ClientRequestRateLimit: int(data.ClientRequestRateLimit.ValueInt64()),
+		Config: &identitynow.Config{
+			URL:                    data.ApiUrl.ValueString(),
+			ClientId:               data.ClientId.ValueString(),
+			ClientSecret:           data.ClientSecret.ValueString(),
+			Credentials:            credentials,
+			MaxClientPoolSize:      int(data.MaxClientPoolSize.ValueInt64()),
+			DefaultClientPoolSize:  int(data.DefaultClientPoolSize.ValueInt64()),
+			ClientRequestRateLimit: int(data.ClientRequestRateLimit.ValueInt64()),
+			MaxRetries:             int(data.MaxRetries.ValueInt64()),
+			APIVersion:             data.ApiVersion.ValueString(),
+			APIVersionOverrides:    apiVersionOverrides,
+			HTTPTimeout:            time.Duration(data.HttpTimeout.ValueInt64()) * time.Second,
+			ReadCacheTTL:           time.Duration(data.ReadCacheTtl.ValueInt64()) * time.Second,
+			ReadCacheMaxEntries:    int(data.ReadCacheMaxEntries.ValueInt64()),
+			AccessToken:            data.AccessToken.ValueString(),
+			TokenFile:              data.TokenFile.ValueString(),
+			AuditLogPath:           data.AuditLogPath.ValueString(),
+			ReadOnly:               data.ReadOnly.ValueBool(),
+			RedactionKeys:          redactionKeys,
+			TokenCache:             cache,
+			AuditLog:               audit,
+			Redactor:               redactor,
+			Transport:              transport,
+		},
 	}
 
 	resp.DataSourceData = config
 	resp.ResourceData = config
+	resp.ListResourceData = config
 
 	tflog.Info(ctx, "Successfully configured IdentityNow provider")
 }
 
+// authModeName describes the configured authentication mode for logging
+func authModeName(data IdentityNowProviderModel) string {
+	switch {
+	case !data.AccessToken.IsNull():
+		return identitynow.AuthModeAccessToken
+	case !data.TokenFile.IsNull():
+		return identitynow.AuthModeTokenFile
+	case !data.PatId.IsNull():
+		return "personal_access_token"
+	}
+	return identitynow.AuthModeClientCredentials
+}
+
 // Resources returns the list of resources for this provider
 func (p *IdentityNowProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
@@ -246,6 +683,27 @@ func (p *IdentityNowProvider) Resources(ctx context.Context) []func() resource.R
 	}
 }
 
+// ListResources returns the list resources queried by `terraform query`
+func (p *IdentityNowProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewSourceListResource,
+		NewAccessProfileListResource,
+		NewRoleListResource,
+		NewGovernanceGroupListResource,
+		NewWorkflowListResource,
+	}
+}
+
+// Functions returns the provider-defined functions, called as provider::identitynow::<name>
+func (p *IdentityNowProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewFilterFunction,
+		NewFilterAndFunction,
+		NewFilterOrFunction,
+		NewCriteriaFunction,
+	}
+}
+
 // DataSources returns the list of data sources for this provider
 func (p *IdentityNowProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
@@ -1,11 +1,23 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
+	"github.com/plsph/terraform-provider-identitynow/identitynow/identitynowtest"
 )
 
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -21,6 +33,19 @@ func TestProvider(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {
+	// Replayed cassettes need no tenant, only the settings the provider configuration requires
+	if os.Getenv("IDENTITYNOW_CASSETTE_MODE") == identitynow.CassetteModeReplay {
+		for name, value := range map[string]string{
+			"IDENTITYNOW_URL":           "https://replay.invalid",
+			"IDENTITYNOW_CLIENT_ID":     "replay",
+			"IDENTITYNOW_CLIENT_SECRET": "replay",
+		} {
+			if os.Getenv(name) == "" {
+				t.Setenv(name, value)
+			}
+		}
+		return
+	}
 	if v := os.Getenv("IDENTITYNOW_URL"); v == "" {
 		t.Fatal("IDENTITYNOW_URL must be set for acceptance tests")
 	}
@@ -46,3 +71,555 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("IDENTITYNOW_CLUSTER_NAME must be set for acceptance tests")
 	}
 }
+
+// setFakeProviderEnv points provider configurations without explicit settings at a fake tenant
+func setFakeProviderEnv(t *testing.T, fake *identitynowtest.Server) {
+	t.Helper()
+	t.Setenv("IDENTITYNOW_URL", fake.URL)
+	t.Setenv("IDENTITYNOW_CLIENT_ID", identitynowtest.ClientID)
+	t.Setenv("IDENTITYNOW_CLIENT_SECRET", identitynowtest.ClientSecret)
+}
+
+// fakeClient returns a client of a fake tenant, e.g. to create objects a test reads through the provider
+func fakeClient(fake *identitynowtest.Server) *identitynow.Client {
+	return identitynow.NewClient(context.Background(), fake.URL, identitynowtest.ClientID, identitynowtest.ClientSecret, 1000, 3)
+}
+
+// testObjectValue builds a value of the given schema type, attributes missing from values are null
+func testObjectValue(schemaType tftypes.Type, values map[string]tftypes.Value) tftypes.Value {
+	objectType := schemaType.(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if v, ok := values[name]; ok {
+			attributes[name] = v
+		}
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// testProtoValue builds a value of the given schema type for protocol requests
+func testProtoValue(t *testing.T, schemaType tftypes.Type, values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+	value, err := tfprotov6.NewDynamicValue(schemaType, testObjectValue(schemaType, values))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return &value
+}
+
+func TestProviderResourcesHaveIdentity(t *testing.T) {
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := context.Background()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil || len(identities.Diagnostics) > 0 {
+		t.Fatalf("unexpected identity schemas result %+v, %v", identities.Diagnostics, err)
+	}
+
+	for typeName := range schemas.ResourceSchemas {
+		if _, ok := identities.IdentitySchemas[typeName]; !ok {
+			t.Errorf("%s has no identity schema", typeName)
+		}
+	}
+}
+
+func TestDimensionImportsByIdentity(t *testing.T) {
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := context.Background()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	identityType := identities.IdentitySchemas["identitynow_dimension"].ValueType()
+	identity := testProtoValue(t, identityType, map[string]tftypes.Value{
+		"role_id":      tftypes.NewValue(tftypes.String, "role-1"),
+		"dimension_id": tftypes.NewValue(tftypes.String, "dimension-1"),
+	})
+	imported, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{
+		TypeName: "identitynow_dimension",
+		Identity: &tfprotov6.ResourceIdentityData{IdentityData: identity},
+	})
+	if err != nil || len(imported.Diagnostics) > 0 || len(imported.ImportedResources) != 1 {
+		t.Fatalf("unexpected import result %+v, %v", imported, err)
+	}
+
+	state, err := imported.ImportedResources[0].State.Unmarshal(schemas.ResourceSchemas["identitynow_dimension"].ValueType())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for name, want := range map[string]string{"role_id": "role-1", "id": "dimension-1"} {
+		var got string
+		if err := attributes[name].As(&got); err != nil || got != want {
+			t.Errorf("expected %s %q, got %q (%v)", name, want, got, err)
+		}
+	}
+}
+
+func TestProviderImportsByName(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	setFakeProviderEnv(t, fake)
+	roleID := fake.Seed("roles", map[string]interface{}{"name": "Admins"})
+	quotedID := fake.Seed("sources", map[string]interface{}{"name": `HR "EU"`})
+	fake.Seed("workflows", map[string]interface{}{"name": "Onboarding"})
+	fake.Seed("workflows", map[string]interface{}{"name": "Onboarding"})
+
+	ctx := context.Background()
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: testProtoValue(t, schemas.Provider.ValueType(), nil),
+	})
+	if err != nil || len(configured.Diagnostics) > 0 {
+		t.Fatalf("unexpected configure result %+v, %v", configured.Diagnostics, err)
+	}
+
+	for _, tc := range []struct {
+		typeName string
+		id       string
+		wantID   string
+		wantErr  string
+	}{
+		{typeName: "identitynow_role", id: "name:Admins", wantID: roleID},
+		{typeName: "identitynow_role", id: roleID, wantID: roleID},
+		{typeName: "identitynow_role", id: "name:Missing", wantErr: "Import Name Not Found"},
+		{typeName: "identitynow_source", id: `name:HR "EU"`, wantID: quotedID},
+		{typeName: "identitynow_workflow", id: "name:Onboarding", wantErr: "Ambiguous Import Name"},
+	} {
+		imported, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{TypeName: tc.typeName, ID: tc.id})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.id, err)
+		}
+		if tc.wantErr != "" {
+			if len(imported.Diagnostics) != 1 || imported.Diagnostics[0].Summary != tc.wantErr {
+				t.Errorf("%s: expected %q, got %+v", tc.id, tc.wantErr, imported.Diagnostics)
+			}
+			continue
+		}
+		if len(imported.Diagnostics) > 0 || len(imported.ImportedResources) != 1 {
+			t.Fatalf("%s: unexpected import result %+v", tc.id, imported.Diagnostics)
+		}
+		state, err := imported.ImportedResources[0].State.Unmarshal(schemas.ResourceSchemas[tc.typeName].ValueType())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var attributes map[string]tftypes.Value
+		var id string
+		if err := state.As(&attributes); err != nil || attributes["id"].As(&id) != nil || id != tc.wantID {
+			t.Errorf("%s: expected id %s, got %s", tc.id, tc.wantID, id)
+		}
+	}
+}
+
+func TestProviderReadsDataSourceFromFakeTenant(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	setFakeProviderEnv(t, fake)
+	if _, err := fakeClient(fake).CreateSource(context.Background(), &identitynow.Source{Name: "HR", Connector: "delimited-file", Owner: &identitynow.Owner{Type: "IDENTITY", ID: "owner"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: testProtoValue(t, schemas.Provider.ValueType(), nil),
+	})
+	if err != nil || len(configured.Diagnostics) > 0 {
+		t.Fatalf("unexpected configure result %+v, %v", configured.Diagnostics, err)
+	}
+
+	dataSourceType := schemas.DataSourceSchemas["identitynow_source"].ValueType()
+	read, err := server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: "identitynow_source",
+		Config:   testProtoValue(t, dataSourceType, map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "HR")}),
+	})
+	if err != nil || len(read.Diagnostics) > 0 {
+		t.Fatalf("unexpected read result %+v, %v", read.Diagnostics, err)
+	}
+
+	state, err := read.State.Unmarshal(dataSourceType)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var attributes map[string]tftypes.Value
+	if err := state.As(&attributes); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var connector string
+	if err := attributes["connector"].As(&connector); err != nil || connector != "delimited-file" {
+		t.Fatalf("unexpected connector %q, %v", connector, err)
+	}
+}
+
+func TestProviderReadOnlyRefusesChanges(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	setFakeProviderEnv(t, fake)
+	t.Setenv("IDENTITYNOW_READ_ONLY", "true")
+
+	ctx := context.Background()
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: testProtoValue(t, schemas.Provider.ValueType(), nil),
+	})
+	if err != nil || len(configured.Diagnostics) > 0 {
+		t.Fatalf("unexpected configure result %+v, %v", configured.Diagnostics, err)
+	}
+
+	resourceType := schemas.ResourceSchemas["identitynow_governance_group"].ValueType()
+	prior, err := tfprotov6.NewDynamicValue(resourceType, tftypes.NewValue(resourceType, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	planned := testProtoValue(t, resourceType, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "Approvers"),
+		"id":   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	applied, err := server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     "identitynow_governance_group",
+		PriorState:   &prior,
+		PlannedState: planned,
+		Config:       testProtoValue(t, resourceType, map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "Approvers")}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(applied.Diagnostics) != 1 || applied.Diagnostics[0].Summary != "IdentityNow provider is read-only" {
+		t.Fatalf("expected a read-only diagnostic, got %+v", applied.Diagnostics)
+	}
+	if n := fake.RequestCount(http.MethodPost, "/v2025/workgroups"); n != 0 {
+		t.Fatalf("expected no request to be sent, got %d", n)
+	}
+}
+
+func TestProviderResourcesHaveTimeouts(t *testing.T) {
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for typeName, resourceSchema := range schemas.ResourceSchemas {
+		var block *tfprotov6.SchemaNestedBlock
+		for _, nested := range resourceSchema.Block.BlockTypes {
+			if nested.TypeName == "timeouts" {
+				block = nested
+			}
+		}
+		if block == nil {
+			t.Errorf("%s has no timeouts block", typeName)
+			continue
+		}
+		operations := map[string]bool{}
+		for _, attribute := range block.Block.Attributes {
+			operations[attribute.Name] = true
+		}
+		for _, operation := range []string{"create", "read", "update", "delete"} {
+			if !operations[operation] {
+				t.Errorf("%s timeouts block has no %s timeout", typeName, operation)
+			}
+		}
+	}
+}
+
+// fakeWorkgroups serves governance groups from memory, the other workgroup calls are not implemented
+type fakeWorkgroups struct {
+	identitynow.WorkgroupsService
+	groups map[string]*identitynow.GovernanceGroup
+}
+
+func (f fakeWorkgroups) GetGovernanceGroups(ctx context.Context, id string) (*identitynow.GovernanceGroup, error) {
+	if group, ok := f.groups[id]; ok {
+		return group, nil
+	}
+	return nil, identitynow.NewNotFoundError(fmt.Sprintf("governance group %s not found", id))
+}
+
+func TestGovernanceGroupResourceReadsFromWorkgroupsService(t *testing.T) {
+	ctx := context.Background()
+	r := &GovernanceGroupResource{client: &Config{services: &identitynow.Services{
+		Workgroups: fakeWorkgroups{groups: map[string]*identitynow.GovernanceGroup{
+			"approvers": {ID: "approvers", Name: "Approvers", GovernanceGroupOwner: &identitynow.GovernanceGroupOwner{Type: "IDENTITY", ID: "owner", Name: "Owner"}},
+		}},
+	}}}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	schemaType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	for id, exists := range map[string]bool{"approvers": true, "deleted": false} {
+		state := tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    testObjectValue(schemaType, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, id)}),
+		}
+		resp := &resource.ReadResponse{State: state}
+		r.Read(ctx, resource.ReadRequest{State: state}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected diagnostics %+v", id, resp.Diagnostics)
+		}
+
+		if !exists {
+			if !resp.State.Raw.IsNull() {
+				t.Fatalf("%s: expected the resource to be removed from state", id)
+			}
+			continue
+		}
+		var name types.String
+		resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("name"), &name)...)
+		if name.ValueString() != "Approvers" {
+			t.Fatalf("%s: expected name Approvers, got %s", id, name)
+		}
+	}
+}
+
+func TestExportWritesResourcesAndImports(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	setFakeProviderEnv(t, fake)
+	ctx := context.Background()
+	client := fakeClient(fake)
+	source, err := client.CreateSource(ctx, &identitynow.Source{Name: "HR Feed", Connector: "delimited-file", Owner: &identitynow.Owner{Type: "IDENTITY", ID: "owner", Name: "Owner"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	profile, err := client.CreateAccessProfile(ctx, &identitynow.AccessProfile{
+		Name:                "HR Admins",
+		Description:         "Administrators",
+		AccessProfileOwner:  &identitynow.ObjectInfo{Type: "IDENTITY", ID: "owner", Name: "Owner"},
+		AccessProfileSource: &identitynow.ObjectInfo{Type: "SOURCE", ID: source.ID, Name: source.Name},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	var out strings.Builder
+	if err := runExport(ctx, []string{"-types", "access_profile,source", "-output", dir}, &out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out.String(), "Exported 2 objects") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	profiles, err := os.ReadFile(filepath.Join(dir, "access_profile.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, want := range []string{`resource "identitynow_access_profile" "hr_admins"`, `name        = "HR Admins"`, "id   = identitynow_source.hr_feed.id"} {
+		if !strings.Contains(string(profiles), want) {
+			t.Fatalf("expected %q in access_profile.tf:\n%s", want, profiles)
+		}
+	}
+	imports, err := os.ReadFile(filepath.Join(dir, "imports.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, want := range []string{"to = identitynow_source.hr_feed", "to = identitynow_access_profile.hr_admins", fmt.Sprintf("id = %q", profile.ID)} {
+		if !strings.Contains(string(imports), want) {
+			t.Fatalf("expected %q in imports.tf:\n%s", want, imports)
+		}
+	}
+
+	if err := runExport(ctx, []string{"-types", "identity"}, &out); err == nil || !strings.Contains(err.Error(), "unsupported export types identity") {
+		t.Fatalf("expected an unsupported type error, got %v", err)
+	}
+}
+
+func TestProviderListsRolesFromFakeTenant(t *testing.T) {
+	fake := identitynowtest.NewServer(t)
+	setFakeProviderEnv(t, fake)
+	ctx := context.Background()
+	ids := map[string]string{}
+	for _, name := range []string{"Admin Read", "Admin Write", "User"} {
+		role, err := fakeClient(fake).CreateRole(ctx, &identitynow.Role{Name: name, RoleOwner: &identitynow.ObjectInfo{Type: "IDENTITY", ID: "owner", Name: "Owner"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		ids[name] = role.ID
+	}
+
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: testProtoValue(t, schemas.Provider.ValueType(), nil),
+	})
+	if err != nil || len(configured.Diagnostics) > 0 {
+		t.Fatalf("unexpected configure result %+v, %v", configured.Diagnostics, err)
+	}
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	stream, err := server.(tfprotov6.ListResourceServer).ListResource(ctx, &tfprotov6.ListResourceRequest{
+		TypeName: "identitynow_role",
+		Config: testProtoValue(t, schemas.ListResourceSchemas["identitynow_role"].ValueType(), map[string]tftypes.Value{
+			"name_prefix": tftypes.NewValue(tftypes.String, "Admin"),
+			"filters":     tftypes.NewValue(tftypes.String, `name ne "Admin Write"`),
+		}),
+		IncludeResource: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var listed []string
+	for result := range stream.Results {
+		if len(result.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics %+v", result.Diagnostics)
+		}
+		listed = append(listed, result.DisplayName)
+
+		identity, err := result.Identity.IdentityData.Unmarshal(identities.IdentitySchemas["identitynow_role"].ValueType())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var attributes map[string]tftypes.Value
+		var id string
+		if err := identity.As(&attributes); err != nil || attributes["id"].As(&id) != nil || id != ids[result.DisplayName] {
+			t.Fatalf("unexpected identity %s for %s", identity, result.DisplayName)
+		}
+		if result.Resource == nil {
+			t.Fatalf("expected the resource of %s", result.DisplayName)
+		}
+	}
+	if len(listed) != 1 || listed[0] != "Admin Read" {
+		t.Fatalf("expected only Admin Read, got %v", listed)
+	}
+}
+
+func TestProviderFunctionsBuildFiltersAndCriteria(t *testing.T) {
+	ctx := context.Background()
+	server, err := testAccProtoV6ProviderFactories["identitynow"]()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	call := func(name string, resultType tftypes.Type, args ...tftypes.Value) (tftypes.Value, *tfprotov6.FunctionError) {
+		t.Helper()
+		var arguments []*tfprotov6.DynamicValue
+		for _, arg := range args {
+			argType := arg.Type()
+			if name == "filter" && len(arguments) == 2 {
+				argType = tftypes.DynamicPseudoType
+			}
+			value, err := tfprotov6.NewDynamicValue(argType, arg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			arguments = append(arguments, &value)
+		}
+		resp, err := server.CallFunction(ctx, &tfprotov6.CallFunctionRequest{Name: name, Arguments: arguments})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if resp.Error != nil {
+			return tftypes.Value{}, resp.Error
+		}
+		result, err := resp.Result.Unmarshal(resultType)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return result, nil
+	}
+	str := func(value string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []tftypes.Value
+		want string
+	}{
+		{name: "filter", args: []tftypes.Value{str("name"), str("EQ"), str(`Say "hi" \ bye`)}, want: `name eq "Say \"hi\" \\ bye"`},
+		{name: "filter", args: []tftypes.Value{str("id"), str("in"), tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}}, []tftypes.Value{str("a"), str("b")})}, want: `id in ("a", "b")`},
+		{name: "filter", args: []tftypes.Value{str("enabled"), str("eq"), tftypes.NewValue(tftypes.Bool, true)}, want: `enabled eq true`},
+		{name: "filter", args: []tftypes.Value{str("description"), str("isnull"), tftypes.NewValue(tftypes.DynamicPseudoType, nil)}, want: `description isnull`},
+		{name: "filter_and", args: []tftypes.Value{str(`name sw "A"`), str(`type eq "x" or type eq "y"`), str("")}, want: `name sw "A" and (type eq "x" or type eq "y")`},
+		{name: "filter_or", args: []tftypes.Value{str(`name eq "a or b"`), str(`name eq "c"`)}, want: `name eq "a or b" or name eq "c"`},
+	} {
+		result, funcErr := call(tc.name, tftypes.String, tc.args...)
+		var got string
+		if funcErr != nil || result.As(&got) != nil || got != tc.want {
+			t.Errorf("%s: expected %s, got %s (%v)", tc.name, tc.want, got, funcErr)
+		}
+	}
+	if _, funcErr := call("filter", tftypes.String, str("name"), str("eq"), tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str("a")})); funcErr == nil {
+		t.Errorf("expected eq with a list to fail")
+	}
+
+	criteriaType := criteriaObjectType().TerraformType(ctx)
+	result, funcErr := call("criteria", criteriaType, str(`attribute.department == "IT" && (account[src1].memberOf contains "X" || attribute.location != "US")`))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %v", funcErr)
+	}
+	for path, want := range map[*tftypes.AttributePath]string{
+		tftypes.NewAttributePath().WithAttributeName("operation"):                                                                                                                                                     "AND",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(0).WithAttributeName("string_value"):                                                                                               "IT",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(0).WithAttributeName("key").WithElementKeyInt(0).WithAttributeName("type"):                                                         "IDENTITY",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(1).WithAttributeName("operation"):                                                                                                  "OR",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(1).WithAttributeName("children").WithElementKeyInt(0).WithAttributeName("operation"):                                               "CONTAINS",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(1).WithAttributeName("children").WithElementKeyInt(0).WithAttributeName("key").WithElementKeyInt(0).WithAttributeName("source_id"): "src1",
+		tftypes.NewAttributePath().WithAttributeName("children").WithElementKeyInt(1).WithAttributeName("children").WithElementKeyInt(0).WithAttributeName("key").WithElementKeyInt(0).WithAttributeName("property"):  "attribute.memberOf",
+	} {
+		found, _, err := tftypes.WalkAttributePath(result, path)
+		var got string
+		if err != nil || found.(tftypes.Value).As(&got) != nil || got != want {
+			t.Errorf("%s: expected %s, got %v (%v)", path, want, found, err)
+		}
+	}
+
+	for _, expression := range []string{
+		`attribute.department = "IT"`,
+		`attribute.department == "IT" &&`,
+		`department == "IT"`,
+		`attribute.a == "1" && (attribute.b == "2" || (attribute.c == "3" && (attribute.d == "4" || attribute.e == "5")))`,
+	} {
+		if _, funcErr := call("criteria", criteriaType, str(expression)); funcErr == nil {
+			t.Errorf("%s: expected an error", expression)
+		}
+	}
+}
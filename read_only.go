@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// checkWritable adds an error diagnostic and returns false when the provider is read-only,
+// so resources fail before building any request
+func (cfg *Config) checkWritable(diags *diag.Diagnostics, resourceType string, operation string) bool {
+	if cfg == nil || !cfg.ReadOnly {
+		return true
+	}
+	diags.AddError(
+		"IdentityNow provider is read-only",
+		fmt.Sprintf("Cannot %s %s: the provider is configured with read_only = true (or IDENTITYNOW_READ_ONLY), "+
+			"so it only reads from the tenant. Unset read_only to apply changes.", operation, resourceType),
+	)
+	return false
+}
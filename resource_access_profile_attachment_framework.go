@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &AccessProfileAttachmentResource{}
 var _ resource.ResourceWithImportState = &AccessProfileAttachmentResource{}
+var _ resource.ResourceWithIdentity = &AccessProfileAttachmentResource{}
 
 func NewAccessProfileAttachmentResource() resource.Resource {
 	return &AccessProfileAttachmentResource{}
@@ -25,9 +29,10 @@ type AccessProfileAttachmentResource struct {
 }
 
 type AccessProfileAttachmentResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	SourceAppID    types.String `tfsdk:"source_app_id"`
-	AccessProfiles types.List   `tfsdk:"access_profiles"`
+	ID             types.String   `tfsdk:"id"`
+	SourceAppID    types.String   `tfsdk:"source_app_id"`
+	AccessProfiles types.List     `tfsdk:"access_profiles"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *AccessProfileAttachmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -55,6 +60,30 @@ func (r *AccessProfileAttachmentResource) Schema(ctx context.Context, req resour
 				ElementType:         types.StringType,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// AccessProfileAttachmentIdentityModel is the identity of the attachments, addressed by their source app
+type AccessProfileAttachmentIdentityModel struct {
+	SourceAppID types.String `tfsdk:"source_app_id"`
+}
+
+func (r *AccessProfileAttachmentResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"source_app_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the source app the access profiles are attached to",
+			},
+		},
 	}
 }
 
@@ -71,26 +100,39 @@ func (r *AccessProfileAttachmentResource) Configure(ctx context.Context, req res
 }
 
 func (r *AccessProfileAttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile_attachment", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile_attachment", "create") {
+		return
+	}
+
 	var data AccessProfileAttachmentResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var accessProfiles []string
 	resp.Diagnostics.Append(data.AccessProfiles.ElementsAs(ctx, &accessProfiles, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	attachment := &AccessProfileAttachment{
+	attachment := &identitynow.AccessProfileAttachment{
 		SourceAppId:    data.SourceAppID.ValueString(),
 		AccessProfiles: accessProfiles,
 	}
 
 	tflog.Info(ctx, "Creating Access Profile Attachment", map[string]interface{}{"source_app_id": attachment.SourceAppId})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -105,18 +147,28 @@ func (r *AccessProfileAttachmentResource) Create(ctx context.Context, req resour
 	data.ID = types.StringValue(newAttachment.SourceAppId)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccessProfileAttachmentIdentityModel{SourceAppID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *AccessProfileAttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile_attachment", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data AccessProfileAttachmentResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Access Profile Attachment", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -124,7 +176,7 @@ func (r *AccessProfileAttachmentResource) Read(ctx context.Context, req resource
 
 	attachment, err := client.GetAccessProfileAttachment(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -142,15 +194,29 @@ func (r *AccessProfileAttachmentResource) Read(ctx context.Context, req resource
 	data.AccessProfiles = apList
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccessProfileAttachmentIdentityModel{SourceAppID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *AccessProfileAttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile_attachment", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile_attachment", "update") {
+		return
+	}
+
 	var data AccessProfileAttachmentResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Access Profile Attachment", map[string]interface{}{"id": data.ID.ValueString()})
 
 	var accessProfiles []string
@@ -159,12 +225,12 @@ func (r *AccessProfileAttachmentResource) Update(ctx context.Context, req resour
 		return
 	}
 
-	attachment := &AccessProfileAttachment{
+	attachment := &identitynow.AccessProfileAttachment{
 		SourceAppId:    data.SourceAppID.ValueString(),
 		AccessProfiles: accessProfiles,
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -177,18 +243,32 @@ func (r *AccessProfileAttachmentResource) Update(ctx context.Context, req resour
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccessProfileAttachmentIdentityModel{SourceAppID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *AccessProfileAttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile_attachment", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile_attachment", "delete") {
+		return
+	}
+
 	var data AccessProfileAttachmentResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Access Profile Attachment", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -196,7 +276,7 @@ func (r *AccessProfileAttachmentResource) Delete(ctx context.Context, req resour
 
 	attachment, err := client.GetAccessProfileAttachment(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get access profile attachment: %s", err))
@@ -211,5 +291,8 @@ func (r *AccessProfileAttachmentResource) Delete(ctx context.Context, req resour
 }
 
 func (r *AccessProfileAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile_attachment", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("source_app_id"), req, resp)
 }
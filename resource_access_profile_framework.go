@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -17,10 +18,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &AccessProfileResource{}
 var _ resource.ResourceWithImportState = &AccessProfileResource{}
+var _ resource.ResourceWithIdentity = &AccessProfileResource{}
 
 func NewAccessProfileResource() resource.Resource {
 	return &AccessProfileResource{}
@@ -31,15 +34,16 @@ type AccessProfileResource struct {
 }
 
 type AccessProfileResourceModel struct {
-	ID                  types.String `tfsdk:"id"`
-	Name                types.String `tfsdk:"name"`
-	Description         types.String `tfsdk:"description"`
-	Owner               types.List   `tfsdk:"owner"`
-	Source              types.List   `tfsdk:"source"`
-	Entitlements        types.List   `tfsdk:"entitlements"`
-	AccessRequestConfig types.List   `tfsdk:"access_request_config"`
-	Enabled             types.Bool   `tfsdk:"enabled"`
-	Requestable         types.Bool   `tfsdk:"requestable"`
+	ID                  types.String   `tfsdk:"id"`
+	Name                types.String   `tfsdk:"name"`
+	Description         types.String   `tfsdk:"description"`
+	Owner               types.List     `tfsdk:"owner"`
+	Source              types.List     `tfsdk:"source"`
+	Entitlements        types.List     `tfsdk:"entitlements"`
+	AccessRequestConfig types.List     `tfsdk:"access_request_config"`
+	Enabled             types.Bool     `tfsdk:"enabled"`
+	Requestable         types.Bool     `tfsdk:"requestable"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
 }
 
 type EntitlementRefModel struct {
@@ -103,6 +107,12 @@ func (r *AccessProfileResource) Schema(ctx context.Context, req resource.SchemaR
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
@@ -214,6 +224,10 @@ func (r *AccessProfileResource) Schema(ctx context.Context, req resource.SchemaR
 	}
 }
 
+func (r *AccessProfileResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *AccessProfileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -227,13 +241,26 @@ func (r *AccessProfileResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile", "create") {
+		return
+	}
+
 	var data AccessProfileResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ap := &AccessProfile{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ap := &identitynow.AccessProfile{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -244,7 +271,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 	if len(owners) > 0 {
-		ap.AccessProfileOwner = &ObjectInfo{
+		ap.AccessProfileOwner = &identitynow.ObjectInfo{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -257,7 +284,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 	if len(sources) > 0 {
-		ap.AccessProfileSource = &ObjectInfo{
+		ap.AccessProfileSource = &identitynow.ObjectInfo{
 			ID:   sources[0].ID.ValueString(),
 			Type: sources[0].Type.ValueString(),
 			Name: sources[0].Name.ValueString(),
@@ -282,7 +309,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 			return
 		}
 		for _, em := range entModels {
-			ent := &ObjectInfo{
+			ent := &identitynow.ObjectInfo{
 				ID:   em.ID.ValueString(),
 				Name: em.Name.ValueString(),
 			}
@@ -304,7 +331,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 		}
 		if len(arcModels) > 0 {
 			arc := arcModels[0]
-			config := &AccessRequestConfigList{}
+			config := &identitynow.AccessRequestConfigList{}
 			if !arc.CommentsRequired.IsNull() {
 				config.CommentsRequired = arc.CommentsRequired.ValueBool()
 			}
@@ -324,7 +351,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 					return
 				}
 				for _, s := range schemes {
-					config.ApprovalSchemes = append(config.ApprovalSchemes, &ApprovalSchemes{
+					config.ApprovalSchemes = append(config.ApprovalSchemes, &identitynow.ApprovalSchemes{
 						ApproverType: s.ApproverType.ValueString(),
 						ApproverId:   s.ApproverID.ValueString(),
 					})
@@ -337,7 +364,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 					return
 				}
 				if len(durModels) > 0 {
-					config.MaxPermittedAccessDuration = &MaxPermittedAccessDuration{
+					config.MaxPermittedAccessDuration = &identitynow.MaxPermittedAccessDuration{
 						Value:    int(durModels[0].Value.ValueInt64()),
 						TimeUnit: durModels[0].TimeUnit.ValueString(),
 					}
@@ -349,7 +376,7 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 
 	tflog.Info(ctx, "Creating Access Profile", map[string]interface{}{"name": ap.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.AccessProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -370,16 +397,26 @@ func (r *AccessProfileResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *AccessProfileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data AccessProfileResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.AccessProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -387,7 +424,7 @@ func (r *AccessProfileResource) Read(ctx context.Context, req resource.ReadReque
 
 	ap, err := client.GetAccessProfile(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -398,23 +435,37 @@ func (r *AccessProfileResource) Read(ctx context.Context, req resource.ReadReque
 	r.setStateFromAPI(ctx, &data, ap, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile", "update") {
+		return
+	}
+
 	var data AccessProfileResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.AccessProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
 	}
 
 	// Build update patches
-	updatePatches := []*UpdateAccessProfile{
+	updatePatches := []*identitynow.UpdateAccessProfile{
 		{Op: "replace", Path: "/description", Value: data.Description.ValueString()},
 	}
 
@@ -426,7 +477,7 @@ func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateR
 			return
 		}
 		if len(owners) > 0 {
-			updatePatches = append(updatePatches, &UpdateAccessProfile{
+			updatePatches = append(updatePatches, &identitynow.UpdateAccessProfile{
 				Op:   "replace",
 				Path: "/owner",
 				Value: map[string]interface{}{
@@ -440,14 +491,14 @@ func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateR
 
 	// Enabled
 	if !data.Enabled.IsNull() {
-		updatePatches = append(updatePatches, &UpdateAccessProfile{
+		updatePatches = append(updatePatches, &identitynow.UpdateAccessProfile{
 			Op: "replace", Path: "/enabled", Value: data.Enabled.ValueBool(),
 		})
 	}
 
 	// Requestable
 	if !data.Requestable.IsNull() {
-		updatePatches = append(updatePatches, &UpdateAccessProfile{
+		updatePatches = append(updatePatches, &identitynow.UpdateAccessProfile{
 			Op: "replace", Path: "/requestable", Value: data.Requestable.ValueBool(),
 		})
 	}
@@ -473,7 +524,7 @@ func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateR
 				})
 			}
 		}
-		updatePatches = append(updatePatches, &UpdateAccessProfile{
+		updatePatches = append(updatePatches, &identitynow.UpdateAccessProfile{
 			Op: "replace", Path: "/entitlements", Value: ents,
 		})
 	}
@@ -524,7 +575,7 @@ func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateR
 				}
 			}
 
-			updatePatches = append(updatePatches, &UpdateAccessProfile{
+			updatePatches = append(updatePatches, &identitynow.UpdateAccessProfile{
 				Op: "replace", Path: "/accessRequestConfig", Value: arcValue,
 			})
 		}
@@ -546,16 +597,30 @@ func (r *AccessProfileResource) Update(ctx context.Context, req resource.UpdateR
 	r.setStateFromAPI(ctx, &data, ap, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_access_profile", "delete") {
+		return
+	}
+
 	var data AccessProfileResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.AccessProfiles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -563,7 +628,7 @@ func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteR
 
 	ap, err := client.GetAccessProfile(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", err.Error())
@@ -572,7 +637,13 @@ func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteR
 
 	// Auto-detach from source apps before deletion
 	if ap.AccessProfileSource != nil && ap.AccessProfileSource.ID != nil {
-		sourceApps, err := client.GetSourceAppsAll(ctx)
+		sourceAppClient, err := r.client.SourceApps(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", err.Error())
+			return
+		}
+
+		sourceApps, err := sourceAppClient.GetSourceAppsAll(ctx)
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error",
 				fmt.Sprintf("Failed to query source apps: %s", err.Error()))
@@ -581,7 +652,7 @@ func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteR
 
 		apId := data.ID.ValueString()
 		for _, sa := range sourceApps {
-			attachment, err := client.GetAccessProfileAttachment(ctx, sa.ID)
+			attachment, err := sourceAppClient.GetAccessProfileAttachment(ctx, sa.ID)
 			if err != nil {
 				resp.Diagnostics.AddError("Client Error",
 					fmt.Sprintf("Failed to get access profile attachments for source app %s: %s", sa.ID, err.Error()))
@@ -590,11 +661,11 @@ func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteR
 
 			for _, attachedId := range attachment.AccessProfiles {
 				if attachedId == apId {
-					detach := &AccessProfileAttachment{
+					detach := &identitynow.AccessProfileAttachment{
 						SourceAppId:    sa.ID,
 						AccessProfiles: []string{apId},
 					}
-					if err := client.DeleteAccessProfileAttachment(ctx, detach); err != nil {
+					if err := sourceAppClient.DeleteAccessProfileAttachment(ctx, detach); err != nil {
 						resp.Diagnostics.AddError("Client Error",
 							fmt.Sprintf("Failed to detach access profile %s from source app %s: %s", apId, sa.ID, err.Error()))
 						return
@@ -617,10 +688,18 @@ func (r *AccessProfileResource) Delete(ctx context.Context, req resource.DeleteR
 }
 
 func (r *AccessProfileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_access_profile", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, accessProfileListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
 
-func (r *AccessProfileResource) setStateFromAPI(ctx context.Context, data *AccessProfileResourceModel, ap *AccessProfile, diags *diag.Diagnostics) {
+func (r *AccessProfileResource) setStateFromAPI(ctx context.Context, data *AccessProfileResourceModel, ap *identitynow.AccessProfile, diags *diag.Diagnostics) {
 	data.Name = types.StringValue(ap.Name)
 	data.Description = types.StringValue(ap.Description)
 	if ap.Enabled != nil {
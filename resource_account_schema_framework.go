@@ -3,18 +3,25 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &AccountSchemaResource{}
+var _ resource.ResourceWithImportState = &AccountSchemaResource{}
+var _ resource.ResourceWithIdentity = &AccountSchemaResource{}
 
 func NewAccountSchemaResource() resource.Resource {
 	return &AccountSchemaResource{}
@@ -37,6 +44,7 @@ type AccountSchemaResourceModel struct {
 	Modified           types.String `tfsdk:"modified"`
 	Created            types.String `tfsdk:"created"`
 	Attributes         types.List   `tfsdk:"attributes"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 type AccountSchemaAttributeModel struct {
@@ -77,10 +85,16 @@ func (r *AccountSchemaResource) Schema(ctx context.Context, req resource.SchemaR
 			"source_id": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Source ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"schema_id": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Schema ID",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"display_attribute": schema.StringAttribute{
 				Optional:            true,
@@ -114,6 +128,12 @@ func (r *AccountSchemaResource) Schema(ctx context.Context, req resource.SchemaR
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"attributes": schema.ListNestedBlock{
 				MarkdownDescription: "Schema attributes",
 				NestedObject: schema.NestedBlockObject{
@@ -170,6 +190,27 @@ func (r *AccountSchemaResource) Schema(ctx context.Context, req resource.SchemaR
 	}
 }
 
+// AccountSchemaIdentityModel is the identity of an account schema, addressed within its source
+type AccountSchemaIdentityModel struct {
+	SourceID types.String `tfsdk:"source_id"`
+	SchemaID types.String `tfsdk:"schema_id"`
+}
+
+func (r *AccountSchemaResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"source_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the source the schema belongs to",
+			},
+			"schema_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the account schema",
+			},
+		},
+	}
+}
+
 func (r *AccountSchemaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -183,13 +224,26 @@ func (r *AccountSchemaResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *AccountSchemaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_account_schema", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_account_schema", "create") {
+		return
+	}
+
 	var data AccountSchemaResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -201,7 +255,7 @@ func (r *AccountSchemaResource) Create(ctx context.Context, req resource.CreateR
 	// Get existing account schema
 	existingSchema, err := client.GetAccountSchema(ctx, sourceID, schemaID)
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -220,7 +274,7 @@ func (r *AccountSchemaResource) Create(ctx context.Context, req resource.CreateR
 
 	// Deduplicate attributes
 	seen := make(map[string]bool)
-	var result []*AccountSchemaAttribute
+	var result []*identitynow.AccountSchemaAttribute
 	for _, attr := range attrs {
 		if _, ok := seen[attr.Name]; !ok {
 			seen[attr.Name] = true
@@ -241,21 +295,31 @@ func (r *AccountSchemaResource) Create(ctx context.Context, req resource.CreateR
 	r.setStateFromAPI(ctx, &data, accountSchemaResponse, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccountSchemaIdentityModel{SourceID: data.SourceID, SchemaID: data.SchemaID}, &resp.Diagnostics)
 }
 
 func (r *AccountSchemaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_account_schema", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data AccountSchemaResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	sourceID := data.SourceID.ValueString()
 	schemaID := data.SchemaID.ValueString()
 
 	tflog.Info(ctx, "Reading Account Schema", map[string]interface{}{"source_id": sourceID})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -263,7 +327,7 @@ func (r *AccountSchemaResource) Read(ctx context.Context, req resource.ReadReque
 
 	accountSchema, err := client.GetAccountSchema(ctx, sourceID, schemaID)
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -280,26 +344,40 @@ func (r *AccountSchemaResource) Read(ctx context.Context, req resource.ReadReque
 	r.setStateFromAPI(ctx, &data, accountSchema, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccountSchemaIdentityModel{SourceID: data.SourceID, SchemaID: data.SchemaID}, &resp.Diagnostics)
 }
 
 func (r *AccountSchemaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_account_schema", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_account_schema", "update") {
+		return
+	}
+
 	var data AccountSchemaResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	sourceID := data.SourceID.ValueString()
 
 	tflog.Info(ctx, "Updating Account Schema", map[string]interface{}{"source_id": sourceID})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
 	}
 
-	accountSchema := &AccountSchema{
+	accountSchema := &identitynow.AccountSchema{
 		ID:                 data.SchemaID.ValueString(),
 		SourceID:           sourceID,
 		Name:               data.Name.ValueString(),
@@ -326,21 +404,35 @@ func (r *AccountSchemaResource) Update(ctx context.Context, req resource.UpdateR
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, AccountSchemaIdentityModel{SourceID: data.SourceID, SchemaID: data.SchemaID}, &resp.Diagnostics)
 }
 
 func (r *AccountSchemaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_account_schema", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_account_schema", "delete") {
+		return
+	}
+
 	var data AccountSchemaResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	sourceID := data.SourceID.ValueString()
 	schemaID := data.SchemaID.ValueString()
 
 	tflog.Info(ctx, "Deleting Account Schema", map[string]interface{}{"source_id": sourceID})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -348,7 +440,7 @@ func (r *AccountSchemaResource) Delete(ctx context.Context, req resource.DeleteR
 
 	accountSchema, err := client.GetAccountSchema(ctx, sourceID, schemaID)
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account schema: %s", err))
@@ -364,7 +456,34 @@ func (r *AccountSchemaResource) Delete(ctx context.Context, req resource.DeleteR
 	}
 }
 
-func (r *AccountSchemaResource) buildAttributes(ctx context.Context, data AccountSchemaResourceModel, diags *diag.Diagnostics) []*AccountSchemaAttribute {
+func (r *AccountSchemaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_account_schema", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	var identity AccountSchemaIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.SplitN(req.ID, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import ID in the format 'source_id/schema_id', got: %s", req.ID),
+			)
+			return
+		}
+		identity = AccountSchemaIdentityModel{SourceID: types.StringValue(parts[0]), SchemaID: types.StringValue(parts[1])}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("source_id"), identity.SourceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("schema_id"), identity.SchemaID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.SchemaID)...)
+}
+
+func (r *AccountSchemaResource) buildAttributes(ctx context.Context, data AccountSchemaResourceModel, diags *diag.Diagnostics) []*identitynow.AccountSchemaAttribute {
 	if data.Attributes.IsNull() {
 		return nil
 	}
@@ -375,9 +494,9 @@ func (r *AccountSchemaResource) buildAttributes(ctx context.Context, data Accoun
 		return nil
 	}
 
-	var attrs []*AccountSchemaAttribute
+	var attrs []*identitynow.AccountSchemaAttribute
 	for _, am := range attrModels {
-		attr := &AccountSchemaAttribute{
+		attr := &identitynow.AccountSchemaAttribute{
 			Name:          am.Name.ValueString(),
 			Type:          am.Type.ValueString(),
 			Description:   am.Description.ValueString(),
@@ -393,7 +512,7 @@ func (r *AccountSchemaResource) buildAttributes(ctx context.Context, data Accoun
 				return nil
 			}
 			if len(schemaModels) > 0 {
-				attr.Schema = &AccountSchemaAttributeSchema{
+				attr.Schema = &identitynow.AccountSchemaAttributeSchema{
 					ID:   schemaModels[0].ID.ValueString(),
 					Name: schemaModels[0].Name.ValueString(),
 					Type: schemaModels[0].Type.ValueString(),
@@ -406,7 +525,7 @@ func (r *AccountSchemaResource) buildAttributes(ctx context.Context, data Accoun
 	return attrs
 }
 
-func (r *AccountSchemaResource) setStateFromAPI(ctx context.Context, data *AccountSchemaResourceModel, as *AccountSchema, diags *diag.Diagnostics) {
+func (r *AccountSchemaResource) setStateFromAPI(ctx context.Context, data *AccountSchemaResourceModel, as *identitynow.AccountSchema, diags *diag.Diagnostics) {
 	data.ID = types.StringValue(as.ID)
 	data.Name = types.StringValue(as.Name)
 	data.SourceID = types.StringValue(as.SourceID)
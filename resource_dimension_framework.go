@@ -5,18 +5,22 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &DimensionResource{}
 var _ resource.ResourceWithImportState = &DimensionResource{}
+var _ resource.ResourceWithIdentity = &DimensionResource{}
 
 func NewDimensionResource() resource.Resource {
 	return &DimensionResource{}
@@ -27,14 +31,15 @@ type DimensionResource struct {
 }
 
 type DimensionResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	RoleID         types.String `tfsdk:"role_id"`
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	Owner          types.List   `tfsdk:"owner"`
-	AccessProfiles types.List   `tfsdk:"access_profiles"`
-	Entitlements   types.List   `tfsdk:"entitlements"`
-	Membership     types.List   `tfsdk:"membership"`
+	ID             types.String   `tfsdk:"id"`
+	RoleID         types.String   `tfsdk:"role_id"`
+	Name           types.String   `tfsdk:"name"`
+	Description    types.String   `tfsdk:"description"`
+	Owner          types.List     `tfsdk:"owner"`
+	AccessProfiles types.List     `tfsdk:"access_profiles"`
+	Entitlements   types.List     `tfsdk:"entitlements"`
+	Membership     types.List     `tfsdk:"membership"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *DimensionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -72,6 +77,12 @@ func (r *DimensionResource) Schema(ctx context.Context, req resource.SchemaReque
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				MarkdownDescription: "Dimension owner",
 				NestedObject: schema.NestedBlockObject{
@@ -224,6 +235,27 @@ func (r *DimensionResource) Schema(ctx context.Context, req resource.SchemaReque
 	}
 }
 
+// DimensionIdentityModel is the identity of a dimension, addressed within its role
+type DimensionIdentityModel struct {
+	RoleID      types.String `tfsdk:"role_id"`
+	DimensionID types.String `tfsdk:"dimension_id"`
+}
+
+func (r *DimensionResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"role_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the role the dimension belongs to",
+			},
+			"dimension_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the dimension",
+			},
+		},
+	}
+}
+
 func (r *DimensionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -242,6 +274,13 @@ func (r *DimensionResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func (r *DimensionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_dimension", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_dimension", "create") {
+		return
+	}
+
 	var data DimensionResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -249,7 +288,13 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	dimension := &Dimension{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dimension := &identitynow.Dimension{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -261,7 +306,7 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 	if len(owners) > 0 {
-		dimension.Owner = &ObjectInfo{
+		dimension.Owner = &identitynow.ObjectInfo{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -275,9 +320,9 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		dimension.AccessProfiles = make([]*ObjectInfo, len(aps))
+		dimension.AccessProfiles = make([]*identitynow.ObjectInfo, len(aps))
 		for i, ap := range aps {
-			dimension.AccessProfiles[i] = &ObjectInfo{
+			dimension.AccessProfiles[i] = &identitynow.ObjectInfo{
 				ID:   ap.ID.ValueString(),
 				Type: ap.Type.ValueString(),
 				Name: ap.Name.ValueString(),
@@ -292,9 +337,9 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		dimension.Entitlements = make([]*ObjectInfo, len(ents))
+		dimension.Entitlements = make([]*identitynow.ObjectInfo, len(ents))
 		for i, e := range ents {
-			dimension.Entitlements[i] = &ObjectInfo{
+			dimension.Entitlements[i] = &identitynow.ObjectInfo{
 				ID:   e.ID.ValueString(),
 				Type: e.Type.ValueString(),
 				Name: e.Name.ValueString(),
@@ -322,7 +367,7 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 		"role_id": data.RoleID.ValueString(),
 	})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -338,9 +383,13 @@ func (r *DimensionResource) Create(ctx context.Context, req resource.CreateReque
 
 	tflog.Trace(ctx, "created a dimension resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, DimensionIdentityModel{RoleID: data.RoleID, DimensionID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *DimensionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_dimension", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data DimensionResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -348,12 +397,18 @@ func (r *DimensionResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Dimension", map[string]interface{}{
 		"id":      data.ID.ValueString(),
 		"role_id": data.RoleID.ValueString(),
 	})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -361,7 +416,7 @@ func (r *DimensionResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	dimension, err := client.GetDimension(ctx, data.RoleID.ValueString(), data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -438,9 +493,17 @@ func (r *DimensionResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, DimensionIdentityModel{RoleID: data.RoleID, DimensionID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_dimension", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_dimension", "update") {
+		return
+	}
+
 	var data DimensionResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -448,21 +511,27 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Dimension", map[string]interface{}{
 		"id":      data.ID.ValueString(),
 		"role_id": data.RoleID.ValueString(),
 	})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
 	}
 
-	updatePatches := []*UpdateDimension{}
+	updatePatches := []*identitynow.UpdateDimension{}
 
 	if !data.Description.IsNull() {
-		updatePatches = append(updatePatches, &UpdateDimension{
+		updatePatches = append(updatePatches, &identitynow.UpdateDimension{
 			Op:    "replace",
 			Path:  "/description",
 			Value: data.Description.ValueString(),
@@ -476,7 +545,7 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 	if len(owners) > 0 {
-		updatePatches = append(updatePatches, &UpdateDimension{
+		updatePatches = append(updatePatches, &identitynow.UpdateDimension{
 			Op:   "replace",
 			Path: "/owner",
 			Value: map[string]interface{}{
@@ -502,7 +571,7 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 				"name": ap.Name.ValueString(),
 			}
 		}
-		updatePatches = append(updatePatches, &UpdateDimension{
+		updatePatches = append(updatePatches, &identitynow.UpdateDimension{
 			Op:    "replace",
 			Path:  "/accessProfiles",
 			Value: apValues,
@@ -524,7 +593,7 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 				"name": e.Name.ValueString(),
 			}
 		}
-		updatePatches = append(updatePatches, &UpdateDimension{
+		updatePatches = append(updatePatches, &identitynow.UpdateDimension{
 			Op:    "replace",
 			Path:  "/entitlements",
 			Value: entValues,
@@ -543,7 +612,7 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 			if resp.Diagnostics.HasError() {
 				return
 			}
-			updatePatches = append(updatePatches, &UpdateDimension{
+			updatePatches = append(updatePatches, &identitynow.UpdateDimension{
 				Op:    "replace",
 				Path:  "/membership",
 				Value: membership,
@@ -558,9 +627,17 @@ func (r *DimensionResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, DimensionIdentityModel{RoleID: data.RoleID, DimensionID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *DimensionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_dimension", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_dimension", "delete") {
+		return
+	}
+
 	var data DimensionResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -568,12 +645,18 @@ func (r *DimensionResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Dimension", map[string]interface{}{
 		"id":      data.ID.ValueString(),
 		"role_id": data.RoleID.ValueString(),
 	})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -581,7 +664,7 @@ func (r *DimensionResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	err = client.DeleteDimension(ctx, data.RoleID.ValueString(), data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete dimension: %s", err))
@@ -590,15 +673,27 @@ func (r *DimensionResource) Delete(ctx context.Context, req resource.DeleteReque
 }
 
 func (r *DimensionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.SplitN(req.ID, "/", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import ID in the format 'role_id/dimension_id', got: %s", req.ID),
-		)
-		return
+	ctx, span := startOperationSpan(ctx, "identitynow_dimension", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	var identity DimensionIdentityModel
+	if req.ID == "" {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		parts := strings.SplitN(req.ID, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import ID in the format 'role_id/dimension_id', got: %s", req.ID),
+			)
+			return
+		}
+		identity = DimensionIdentityModel{RoleID: types.StringValue(parts[0]), DimensionID: types.StringValue(parts[1])}
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_id"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_id"), identity.RoleID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.DimensionID)...)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -12,10 +13,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &GovernanceGroupResource{}
 var _ resource.ResourceWithImportState = &GovernanceGroupResource{}
+var _ resource.ResourceWithIdentity = &GovernanceGroupResource{}
 
 func NewGovernanceGroupResource() resource.Resource {
 	return &GovernanceGroupResource{}
@@ -26,10 +29,11 @@ type GovernanceGroupResource struct {
 }
 
 type GovernanceGroupResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Owner       types.List   `tfsdk:"owner"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Owner       types.List     `tfsdk:"owner"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 type GovernanceGroupOwnerModel struct {
@@ -63,6 +67,12 @@ func (r *GovernanceGroupResource) Schema(ctx context.Context, req resource.Schem
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				MarkdownDescription: "Governance Group owner",
 				NestedObject: schema.NestedBlockObject{
@@ -87,6 +97,10 @@ func (r *GovernanceGroupResource) Schema(ctx context.Context, req resource.Schem
 	}
 }
 
+func (r *GovernanceGroupResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *GovernanceGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -100,13 +114,26 @@ func (r *GovernanceGroupResource) Configure(ctx context.Context, req resource.Co
 }
 
 func (r *GovernanceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group", "create") {
+		return
+	}
+
 	var data GovernanceGroupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	gg := &GovernanceGroup{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gg := &identitynow.GovernanceGroup{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -117,7 +144,7 @@ func (r *GovernanceGroupResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 	if len(owners) > 0 {
-		gg.GovernanceGroupOwner = &GovernanceGroupOwner{
+		gg.GovernanceGroupOwner = &identitynow.GovernanceGroupOwner{
 			ID:   owners[0].ID.ValueString(),
 			Name: owners[0].Name.ValueString(),
 			Type: owners[0].Type.ValueString(),
@@ -126,7 +153,7 @@ func (r *GovernanceGroupResource) Create(ctx context.Context, req resource.Creat
 
 	tflog.Info(ctx, "Creating Governance Group", map[string]interface{}{"name": gg.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -141,18 +168,28 @@ func (r *GovernanceGroupResource) Create(ctx context.Context, req resource.Creat
 	data.ID = types.StringValue(newGG.ID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data GovernanceGroupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Governance Group", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -160,7 +197,7 @@ func (r *GovernanceGroupResource) Read(ctx context.Context, req resource.ReadReq
 
 	gg, err := client.GetGovernanceGroups(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -192,18 +229,32 @@ func (r *GovernanceGroupResource) Read(ctx context.Context, req resource.ReadReq
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group", "update") {
+		return
+	}
+
 	var data GovernanceGroupResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Governance Group", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -224,7 +275,7 @@ func (r *GovernanceGroupResource) Update(ctx context.Context, req resource.Updat
 		}
 	}
 
-	updatePatches := []*UpdateGovernanceGroup{
+	updatePatches := []*identitynow.UpdateGovernanceGroup{
 		{Op: "replace", Path: "/name", Value: data.Name.ValueString()},
 		{Op: "replace", Path: "/description", Value: data.Description.ValueString()},
 		{Op: "replace", Path: "/owner", Value: ownerValue},
@@ -237,18 +288,32 @@ func (r *GovernanceGroupResource) Update(ctx context.Context, req resource.Updat
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group", "delete") {
+		return
+	}
+
 	var data GovernanceGroupResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Governance Group", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -256,7 +321,7 @@ func (r *GovernanceGroupResource) Delete(ctx context.Context, req resource.Delet
 
 	gg, err := client.GetGovernanceGroups(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get governance group: %s", err))
@@ -271,5 +336,13 @@ func (r *GovernanceGroupResource) Delete(ctx context.Context, req resource.Delet
 }
 
 func (r *GovernanceGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, governanceGroupListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
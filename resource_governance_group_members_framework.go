@@ -3,19 +3,27 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
+// membersTimeout is the default deadline of every operation, the member lists are small
+const membersTimeout = time.Minute
+
 var _ resource.Resource = &GovernanceGroupMembersResource{}
 var _ resource.ResourceWithImportState = &GovernanceGroupMembersResource{}
+var _ resource.ResourceWithIdentity = &GovernanceGroupMembersResource{}
 
 func NewGovernanceGroupMembersResource() resource.Resource {
 	return &GovernanceGroupMembersResource{}
@@ -26,9 +34,10 @@ type GovernanceGroupMembersResource struct {
 }
 
 type GovernanceGroupMembersResourceModel struct {
-	ID                types.String `tfsdk:"id"`
-	GovernanceGroupID types.String `tfsdk:"governance_group_id"`
-	Members           types.List   `tfsdk:"members"`
+	ID                types.String   `tfsdk:"id"`
+	GovernanceGroupID types.String   `tfsdk:"governance_group_id"`
+	Members           types.List     `tfsdk:"members"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
 }
 
 type GovernanceGroupMemberModel struct {
@@ -58,6 +67,12 @@ func (r *GovernanceGroupMembersResource) Schema(ctx context.Context, req resourc
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"members": schema.ListNestedBlock{
 				MarkdownDescription: "List of members",
 				NestedObject: schema.NestedBlockObject{
@@ -82,6 +97,22 @@ func (r *GovernanceGroupMembersResource) Schema(ctx context.Context, req resourc
 	}
 }
 
+// GovernanceGroupMembersIdentityModel is the identity of the members, addressed by their governance group
+type GovernanceGroupMembersIdentityModel struct {
+	GovernanceGroupID types.String `tfsdk:"governance_group_id"`
+}
+
+func (r *GovernanceGroupMembersResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"governance_group_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the governance group",
+			},
+		},
+	}
+}
+
 func (r *GovernanceGroupMembersResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -95,23 +126,36 @@ func (r *GovernanceGroupMembersResource) Configure(ctx context.Context, req reso
 }
 
 func (r *GovernanceGroupMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group_members", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group_members", "create") {
+		return
+	}
+
 	var data GovernanceGroupMembersResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, membersTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var members []GovernanceGroupMemberModel
 	resp.Diagnostics.Append(data.Members.ElementsAs(ctx, &members, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ggMembers := &GovernanceGroupMembers{
+	ggMembers := &identitynow.GovernanceGroupMembers{
 		GovernanceGroupId: data.GovernanceGroupID.ValueString(),
 	}
 	for _, m := range members {
-		ggMembers.GovernanceGroupMembersMembers = append(ggMembers.GovernanceGroupMembersMembers, &GovernanceGroupMembersMembers{
+		ggMembers.GovernanceGroupMembersMembers = append(ggMembers.GovernanceGroupMembersMembers, &identitynow.GovernanceGroupMembersMembers{
 			ID:   m.ID.ValueString(),
 			Name: m.Name.ValueString(),
 			Type: m.Type.ValueString(),
@@ -120,7 +164,7 @@ func (r *GovernanceGroupMembersResource) Create(ctx context.Context, req resourc
 
 	tflog.Info(ctx, "Creating Governance Group Members", map[string]interface{}{"governance_group_id": ggMembers.GovernanceGroupId})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -135,18 +179,28 @@ func (r *GovernanceGroupMembersResource) Create(ctx context.Context, req resourc
 	data.ID = types.StringValue(newGGMembers.GovernanceGroupId)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, GovernanceGroupMembersIdentityModel{GovernanceGroupID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group_members", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data GovernanceGroupMembersResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, membersTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Governance Group Members", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -154,7 +208,7 @@ func (r *GovernanceGroupMembersResource) Read(ctx context.Context, req resource.
 
 	ggMembers, err := client.GetGovernanceGroupMembers(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -186,15 +240,29 @@ func (r *GovernanceGroupMembersResource) Read(ctx context.Context, req resource.
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, GovernanceGroupMembersIdentityModel{GovernanceGroupID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group_members", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group_members", "update") {
+		return
+	}
+
 	var data GovernanceGroupMembersResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, membersTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Governance Group Members", map[string]interface{}{"id": data.ID.ValueString()})
 
 	var members []GovernanceGroupMemberModel
@@ -203,18 +271,18 @@ func (r *GovernanceGroupMembersResource) Update(ctx context.Context, req resourc
 		return
 	}
 
-	ggMembers := &GovernanceGroupMembers{
+	ggMembers := &identitynow.GovernanceGroupMembers{
 		GovernanceGroupId: data.GovernanceGroupID.ValueString(),
 	}
 	for _, m := range members {
-		ggMembers.GovernanceGroupMembersMembers = append(ggMembers.GovernanceGroupMembersMembers, &GovernanceGroupMembersMembers{
+		ggMembers.GovernanceGroupMembersMembers = append(ggMembers.GovernanceGroupMembersMembers, &identitynow.GovernanceGroupMembersMembers{
 			ID:   m.ID.ValueString(),
 			Name: m.Name.ValueString(),
 			Type: m.Type.ValueString(),
 		})
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -223,7 +291,7 @@ func (r *GovernanceGroupMembersResource) Update(ctx context.Context, req resourc
 	// Get current members for the update call
 	currentMembers, err := client.GetGovernanceGroupMembers(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -238,18 +306,32 @@ func (r *GovernanceGroupMembersResource) Update(ctx context.Context, req resourc
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, GovernanceGroupMembersIdentityModel{GovernanceGroupID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *GovernanceGroupMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group_members", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_governance_group_members", "delete") {
+		return
+	}
+
 	var data GovernanceGroupMembersResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, membersTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Governance Group Members", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workgroups(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -257,7 +339,7 @@ func (r *GovernanceGroupMembersResource) Delete(ctx context.Context, req resourc
 
 	ggMembers, err := client.GetGovernanceGroupMembers(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get governance group members: %s", err))
@@ -272,5 +354,8 @@ func (r *GovernanceGroupMembersResource) Delete(ctx context.Context, req resourc
 }
 
 func (r *GovernanceGroupMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_governance_group_members", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("governance_group_id"), req, resp)
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
@@ -13,9 +15,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &PasswordPolicyResource{}
+var _ resource.ResourceWithImportState = &PasswordPolicyResource{}
+var _ resource.ResourceWithIdentity = &PasswordPolicyResource{}
 
 func NewPasswordPolicyResource() resource.Resource {
 	return &PasswordPolicyResource{}
@@ -57,6 +62,7 @@ type PasswordPolicyResourceModel struct {
 	ConnectedServices                     types.List   `tfsdk:"connected_services"`
 	DateCreated                           types.String `tfsdk:"date_created"`
 	LastUpdated                           types.String `tfsdk:"last_updated"`
+	Timeouts                              timeouts.Value `tfsdk:"timeouts"`
 }
 
 type ConnectedServiceModel struct {
@@ -243,9 +249,21 @@ func (r *PasswordPolicyResource) Schema(ctx context.Context, req resource.Schema
 				MarkdownDescription: "Last updated",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
+func (r *PasswordPolicyResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *PasswordPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -259,12 +277,25 @@ func (r *PasswordPolicyResource) Configure(ctx context.Context, req resource.Con
 }
 
 func (r *PasswordPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_password_policy", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_password_policy", "create") {
+		return
+	}
+
 	var data PasswordPolicyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	pp := r.buildPasswordPolicy(ctx, data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -272,7 +303,7 @@ func (r *PasswordPolicyResource) Create(ctx context.Context, req resource.Create
 
 	tflog.Info(ctx, "Creating Password Policy", map[string]interface{}{"name": pp.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.PasswordPolicies(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -286,18 +317,28 @@ func (r *PasswordPolicyResource) Create(ctx context.Context, req resource.Create
 
 	r.setStateFromAPI(ctx, &data, newPP, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *PasswordPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_password_policy", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data PasswordPolicyResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Password Policy", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.PasswordPolicies(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -305,7 +346,7 @@ func (r *PasswordPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 
 	pp, err := client.GetPasswordPolicy(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -315,15 +356,29 @@ func (r *PasswordPolicyResource) Read(ctx context.Context, req resource.ReadRequ
 
 	r.setStateFromAPI(ctx, &data, pp, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *PasswordPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_password_policy", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_password_policy", "update") {
+		return
+	}
+
 	var data PasswordPolicyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Password Policy", map[string]interface{}{"id": data.ID.ValueString()})
 
 	pp := r.buildPasswordPolicy(ctx, data, &resp.Diagnostics)
@@ -332,7 +387,7 @@ func (r *PasswordPolicyResource) Update(ctx context.Context, req resource.Update
 	}
 	pp.ID = data.ID.ValueString()
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.PasswordPolicies(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -345,18 +400,32 @@ func (r *PasswordPolicyResource) Update(ctx context.Context, req resource.Update
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *PasswordPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_password_policy", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_password_policy", "delete") {
+		return
+	}
+
 	var data PasswordPolicyResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Password Policy", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.PasswordPolicies(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -364,7 +433,7 @@ func (r *PasswordPolicyResource) Delete(ctx context.Context, req resource.Delete
 
 	pp, err := client.GetPasswordPolicy(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get password policy: %s", err))
@@ -378,8 +447,15 @@ func (r *PasswordPolicyResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
-func (r *PasswordPolicyResource) buildPasswordPolicy(ctx context.Context, data PasswordPolicyResourceModel, diags *diag.Diagnostics) *PasswordPolicy {
-	pp := &PasswordPolicy{
+func (r *PasswordPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_password_policy", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+}
+
+func (r *PasswordPolicyResource) buildPasswordPolicy(ctx context.Context, data PasswordPolicyResourceModel, diags *diag.Diagnostics) *identitynow.PasswordPolicy {
+	pp := &identitynow.PasswordPolicy{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -490,7 +566,7 @@ func (r *PasswordPolicyResource) buildPasswordPolicy(ctx context.Context, data P
 	return pp
 }
 
-func (r *PasswordPolicyResource) setStateFromAPI(ctx context.Context, data *PasswordPolicyResourceModel, pp *PasswordPolicy, diags *diag.Diagnostics) {
+func (r *PasswordPolicyResource) setStateFromAPI(ctx context.Context, data *PasswordPolicyResourceModel, pp *identitynow.PasswordPolicy, diags *diag.Diagnostics) {
 	data.ID = types.StringValue(pp.ID)
 	data.Name = types.StringValue(pp.Name)
 	data.Description = types.StringValue(pp.Description)
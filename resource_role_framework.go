@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -14,11 +15,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &RoleResource{}
 var _ resource.ResourceWithImportState = &RoleResource{}
+var _ resource.ResourceWithIdentity = &RoleResource{}
 
 func NewRoleResource() resource.Resource {
 	return &RoleResource{}
@@ -31,18 +34,19 @@ type RoleResource struct {
 
 // RoleResourceModel describes the resource data model
 type RoleResourceModel struct {
-	ID                  types.String `tfsdk:"id"`
-	Name                types.String `tfsdk:"name"`
-	Description         types.String `tfsdk:"description"`
-	Owner               types.List   `tfsdk:"owner"`
-	AccessProfiles      types.List   `tfsdk:"access_profiles"`
-	Entitlements        types.List   `tfsdk:"entitlements"`
-	Membership          types.List   `tfsdk:"membership"`
-	AccessModelMetadata types.List   `tfsdk:"access_model_metadata"`
-	AccessRequestConfig types.List   `tfsdk:"access_request_config"`
-	Requestable         types.Bool   `tfsdk:"requestable"`
-	Dimensional         types.Bool   `tfsdk:"dimensional"`
-	Enabled             types.Bool   `tfsdk:"enabled"`
+	ID                  types.String   `tfsdk:"id"`
+	Name                types.String   `tfsdk:"name"`
+	Description         types.String   `tfsdk:"description"`
+	Owner               types.List     `tfsdk:"owner"`
+	AccessProfiles      types.List     `tfsdk:"access_profiles"`
+	Entitlements        types.List     `tfsdk:"entitlements"`
+	Membership          types.List     `tfsdk:"membership"`
+	AccessModelMetadata types.List     `tfsdk:"access_model_metadata"`
+	AccessRequestConfig types.List     `tfsdk:"access_request_config"`
+	Requestable         types.Bool     `tfsdk:"requestable"`
+	Dimensional         types.Bool     `tfsdk:"dimensional"`
+	Enabled             types.Bool     `tfsdk:"enabled"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
 }
 
 type AccessModelMetadataModel struct {
@@ -175,6 +179,12 @@ func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				MarkdownDescription: "Role owner",
 				NestedObject: schema.NestedBlockObject{
@@ -430,6 +440,10 @@ func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 	}
 }
 
+func (r *RoleResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *RoleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -448,6 +462,13 @@ func (r *RoleResource) Configure(ctx context.Context, req resource.ConfigureRequ
 }
 
 func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_role", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_role", "create") {
+		return
+	}
+
 	var data RoleResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -455,8 +476,14 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Build Role object
-	role := &Role{
+	role := &identitynow.Role{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -468,7 +495,7 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 	if len(owners) > 0 {
-		role.RoleOwner = &ObjectInfo{
+		role.RoleOwner = &identitynow.ObjectInfo{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -482,9 +509,9 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		role.AccessProfiles = make([]*ObjectInfo, len(aps))
+		role.AccessProfiles = make([]*identitynow.ObjectInfo, len(aps))
 		for i, ap := range aps {
-			role.AccessProfiles[i] = &ObjectInfo{
+			role.AccessProfiles[i] = &identitynow.ObjectInfo{
 				ID:   ap.ID.ValueString(),
 				Type: ap.Type.ValueString(),
 				Name: ap.Name.ValueString(),
@@ -499,9 +526,9 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		role.Entitlements = make([]*ObjectInfo, len(ents))
+		role.Entitlements = make([]*identitynow.ObjectInfo, len(ents))
 		for i, e := range ents {
-			role.Entitlements[i] = &ObjectInfo{
+			role.Entitlements[i] = &identitynow.ObjectInfo{
 				ID:   e.ID.ValueString(),
 				Type: e.Type.ValueString(),
 				Name: e.Name.ValueString(),
@@ -557,7 +584,7 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	tflog.Info(ctx, "Creating Role", map[string]interface{}{"name": role.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -595,9 +622,13 @@ func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	tflog.Trace(ctx, "created a role resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_role", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data RoleResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -605,9 +636,15 @@ func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Role", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -615,7 +652,7 @@ func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	role, err := client.GetRole(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -715,9 +752,17 @@ func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_role", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_role", "update") {
+		return
+	}
+
 	var data RoleResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -725,19 +770,25 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Role", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
 	}
 
 	// Build update patches for all mutable fields
-	updatePatches := []*UpdateRole{}
+	updatePatches := []*identitynow.UpdateRole{}
 
 	if !data.Description.IsNull() {
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/description",
 			Value: data.Description.ValueString(),
@@ -751,7 +802,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 	if len(owners) > 0 {
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:   "replace",
 			Path: "/owner",
 			Value: map[string]interface{}{
@@ -779,7 +830,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 				})
 			}
 		}
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/accessProfiles",
 			Value: apValues,
@@ -803,7 +854,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 				})
 			}
 		}
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/entitlements",
 			Value: entValues,
@@ -812,7 +863,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	// Patch requestable
 	if !data.Requestable.IsNull() {
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/requestable",
 			Value: data.Requestable.ValueBool(),
@@ -821,7 +872,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	// Patch dimensional
 	if !data.Dimensional.IsNull() {
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/dimensional",
 			Value: data.Dimensional.ValueBool(),
@@ -830,7 +881,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	// Patch enabled
 	if !data.Enabled.IsNull() {
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/enabled",
 			Value: data.Enabled.ValueBool(),
@@ -849,7 +900,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 			if resp.Diagnostics.HasError() {
 				return
 			}
-			updatePatches = append(updatePatches, &UpdateRole{
+			updatePatches = append(updatePatches, &identitynow.UpdateRole{
 				Op:    "replace",
 				Path:  "/membership",
 				Value: membership,
@@ -863,7 +914,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/accessModelMetadata",
 			Value: metadata,
@@ -876,7 +927,7 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		updatePatches = append(updatePatches, &UpdateRole{
+		updatePatches = append(updatePatches, &identitynow.UpdateRole{
 			Op:    "replace",
 			Path:  "/accessRequestConfig",
 			Value: arcValue,
@@ -919,9 +970,17 @@ func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_role", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_role", "delete") {
+		return
+	}
+
 	var data RoleResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -929,9 +988,15 @@ func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Role", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Roles(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -939,7 +1004,7 @@ func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	role, err := client.GetRole(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get role: %s", err))
@@ -954,7 +1019,15 @@ func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 }
 
 func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_role", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, roleListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
 
 // criteriaKeyBlockObject returns the reusable schema for a criteria key block.
@@ -978,8 +1051,8 @@ func criteriaKeyBlockObject() schema.NestedBlockObject {
 }
 
 // membershipModelToAPI converts the Terraform MembershipModel to the API RoleMembership struct.
-func membershipModelToAPI(ctx context.Context, m MembershipModel, diags *diag.Diagnostics) *RoleMembership {
-	membership := &RoleMembership{
+func membershipModelToAPI(ctx context.Context, m MembershipModel, diags *diag.Diagnostics) *identitynow.RoleMembership {
+	membership := &identitynow.RoleMembership{
 		Type: m.Type.ValueString(),
 	}
 
@@ -1001,8 +1074,8 @@ func membershipModelToAPI(ctx context.Context, m MembershipModel, diags *diag.Di
 }
 
 // criteriaModelToAPI converts a top-level CriteriaModel to the API RoleMembershipCriteria.
-func criteriaModelToAPI(ctx context.Context, c CriteriaModel, diags *diag.Diagnostics) *RoleMembershipCriteria {
-	criteria := &RoleMembershipCriteria{
+func criteriaModelToAPI(ctx context.Context, c CriteriaModel, diags *diag.Diagnostics) *identitynow.RoleMembershipCriteria {
+	criteria := &identitynow.RoleMembershipCriteria{
 		Operation: c.Operation.ValueString(),
 	}
 
@@ -1035,7 +1108,7 @@ func criteriaModelToAPI(ctx context.Context, c CriteriaModel, diags *diag.Diagno
 		if diags.HasError() {
 			return nil
 		}
-		criteria.Children = make([]*RoleMembershipCriteria, len(childModels))
+		criteria.Children = make([]*identitynow.RoleMembershipCriteria, len(childModels))
 		for i, child := range childModels {
 			criteria.Children[i] = criteriaChildModelToAPI(ctx, child, diags)
 			if diags.HasError() {
@@ -1048,8 +1121,8 @@ func criteriaModelToAPI(ctx context.Context, c CriteriaModel, diags *diag.Diagno
 }
 
 // criteriaChildModelToAPI converts a level-2 CriteriaChildModel to the API type.
-func criteriaChildModelToAPI(ctx context.Context, c CriteriaChildModel, diags *diag.Diagnostics) *RoleMembershipCriteria {
-	criteria := &RoleMembershipCriteria{
+func criteriaChildModelToAPI(ctx context.Context, c CriteriaChildModel, diags *diag.Diagnostics) *identitynow.RoleMembershipCriteria {
+	criteria := &identitynow.RoleMembershipCriteria{
 		Operation: c.Operation.ValueString(),
 	}
 
@@ -1081,7 +1154,7 @@ func criteriaChildModelToAPI(ctx context.Context, c CriteriaChildModel, diags *d
 		if diags.HasError() {
 			return nil
 		}
-		criteria.Children = make([]*RoleMembershipCriteria, len(leafModels))
+		criteria.Children = make([]*identitynow.RoleMembershipCriteria, len(leafModels))
 		for i, leaf := range leafModels {
 			criteria.Children[i] = criteriaLeafModelToAPI(ctx, leaf, diags)
 			if diags.HasError() {
@@ -1094,8 +1167,8 @@ func criteriaChildModelToAPI(ctx context.Context, c CriteriaChildModel, diags *d
 }
 
 // criteriaLeafModelToAPI converts a level-3 CriteriaLeafModel to the API type.
-func criteriaLeafModelToAPI(ctx context.Context, c CriteriaLeafModel, diags *diag.Diagnostics) *RoleMembershipCriteria {
-	criteria := &RoleMembershipCriteria{
+func criteriaLeafModelToAPI(ctx context.Context, c CriteriaLeafModel, diags *diag.Diagnostics) *identitynow.RoleMembershipCriteria {
+	criteria := &identitynow.RoleMembershipCriteria{
 		Operation: c.Operation.ValueString(),
 	}
 
@@ -1124,8 +1197,8 @@ func criteriaLeafModelToAPI(ctx context.Context, c CriteriaLeafModel, diags *dia
 }
 
 // criteriaKeyModelToAPI converts a CriteriaKeyModel to the API RoleKey.
-func criteriaKeyModelToAPI(k CriteriaKeyModel) *RoleKey {
-	key := &RoleKey{
+func criteriaKeyModelToAPI(k CriteriaKeyModel) *identitynow.RoleKey {
+	key := &identitynow.RoleKey{
 		Type:     k.Type.ValueString(),
 		Property: k.Property.ValueString(),
 	}
@@ -1136,7 +1209,7 @@ func criteriaKeyModelToAPI(k CriteriaKeyModel) *RoleKey {
 }
 
 // membershipAPIToState converts the API RoleMembership to Terraform state list value.
-func membershipAPIToState(ctx context.Context, m *RoleMembership, diags *diag.Diagnostics) types.List {
+func membershipAPIToState(ctx context.Context, m *identitynow.RoleMembership, diags *diag.Diagnostics) types.List {
 	membershipObjType := membershipObjectType()
 
 	if m == nil {
@@ -1159,7 +1232,7 @@ func membershipAPIToState(ctx context.Context, m *RoleMembership, diags *diag.Di
 }
 
 // criteriaAPIToState converts API RoleMembershipCriteria to a Terraform list of CriteriaModel.
-func criteriaAPIToState(ctx context.Context, c *RoleMembershipCriteria, diags *diag.Diagnostics) types.List {
+func criteriaAPIToState(ctx context.Context, c *identitynow.RoleMembershipCriteria, diags *diag.Diagnostics) types.List {
 	model := CriteriaModel{
 		Operation: types.StringValue(c.Operation),
 	}
@@ -1203,7 +1276,7 @@ func criteriaAPIToState(ctx context.Context, c *RoleMembershipCriteria, diags *d
 }
 
 // criteriaChildAPIToModel converts an API RoleMembershipCriteria (level 2) to CriteriaChildModel.
-func criteriaChildAPIToModel(ctx context.Context, c *RoleMembershipCriteria, diags *diag.Diagnostics) CriteriaChildModel {
+func criteriaChildAPIToModel(ctx context.Context, c *identitynow.RoleMembershipCriteria, diags *diag.Diagnostics) CriteriaChildModel {
 	model := CriteriaChildModel{
 		Operation: types.StringValue(c.Operation),
 	}
@@ -1244,7 +1317,7 @@ func criteriaChildAPIToModel(ctx context.Context, c *RoleMembershipCriteria, dia
 }
 
 // criteriaLeafAPIToModel converts an API RoleMembershipCriteria (level 3) to CriteriaLeafModel.
-func criteriaLeafAPIToModel(ctx context.Context, c *RoleMembershipCriteria, diags *diag.Diagnostics) CriteriaLeafModel {
+func criteriaLeafAPIToModel(ctx context.Context, c *identitynow.RoleMembershipCriteria, diags *diag.Diagnostics) CriteriaLeafModel {
 	model := CriteriaLeafModel{
 		Operation: types.StringValue(c.Operation),
 	}
@@ -1267,7 +1340,7 @@ func criteriaLeafAPIToModel(ctx context.Context, c *RoleMembershipCriteria, diag
 }
 
 // criteriaKeyAPIToState converts an API RoleKey to a Terraform list value.
-func criteriaKeyAPIToState(ctx context.Context, k *RoleKey, diags *diag.Diagnostics) types.List {
+func criteriaKeyAPIToState(ctx context.Context, k *identitynow.RoleKey, diags *diag.Diagnostics) types.List {
 	keyObjType := criteriaKeyObjectType()
 
 	if k == nil {
@@ -1344,7 +1417,7 @@ func criteriaKeyObjectType() types.ObjectType {
 }
 
 // accessModelMetadataModelToAPI converts the Terraform state list to the API AttributeDTOList.
-func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List, diags *diag.Diagnostics) *AttributeDTOList {
+func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List, diags *diag.Diagnostics) *identitynow.AttributeDTOList {
 	if metadataList.IsNull() || len(metadataList.Elements()) == 0 {
 		return nil
 	}
@@ -1359,7 +1432,7 @@ func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List,
 		return nil
 	}
 
-	result := &AttributeDTOList{}
+	result := &identitynow.AttributeDTOList{}
 	m := metadataModels[0]
 
 	if !m.Attributes.IsNull() && len(m.Attributes.Elements()) > 0 {
@@ -1369,9 +1442,9 @@ func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List,
 			return nil
 		}
 
-		result.Attributes = make([]*AccessModelMetadataAttribute, len(attrModels))
+		result.Attributes = make([]*identitynow.AccessModelMetadataAttribute, len(attrModels))
 		for i, am := range attrModels {
-			apiAttr := &AccessModelMetadataAttribute{
+			apiAttr := &identitynow.AccessModelMetadataAttribute{
 				Key:  am.Key.ValueString(),
 				Name: am.Name.ValueString(),
 			}
@@ -1383,9 +1456,9 @@ func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List,
 					return nil
 				}
 
-				apiAttr.Values = make([]*AccessModelMetadataValue, len(valModels))
+				apiAttr.Values = make([]*identitynow.AccessModelMetadataValue, len(valModels))
 				for j, vm := range valModels {
-					apiVal := &AccessModelMetadataValue{
+					apiVal := &identitynow.AccessModelMetadataValue{
 						Value: vm.Value.ValueString(),
 						Name:  vm.Name.ValueString(),
 					}
@@ -1404,7 +1477,7 @@ func accessModelMetadataModelToAPI(ctx context.Context, metadataList types.List,
 }
 
 // accessModelMetadataAPIToState converts the API AttributeDTOList to a Terraform state list.
-func accessModelMetadataAPIToState(ctx context.Context, metadata *AttributeDTOList, diags *diag.Diagnostics) types.List {
+func accessModelMetadataAPIToState(ctx context.Context, metadata *identitynow.AttributeDTOList, diags *diag.Diagnostics) types.List {
 	metadataObjType := accessModelMetadataObjectType()
 
 	if metadata == nil || len(metadata.Attributes) == 0 {
@@ -1477,7 +1550,7 @@ func accessModelMetadataValueObjectType() types.ObjectType {
 }
 
 // roleAccessRequestConfigModelToAPI converts the Terraform access_request_config list to the API struct.
-func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.List, diags *diag.Diagnostics) *RoleAccessRequestConfig {
+func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.List, diags *diag.Diagnostics) *identitynow.RoleAccessRequestConfig {
 	if configList.IsNull() || len(configList.Elements()) == 0 {
 		return nil
 	}
@@ -1493,7 +1566,7 @@ func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.Lis
 	}
 
 	m := configModels[0]
-	config := &RoleAccessRequestConfig{}
+	config := &identitynow.RoleAccessRequestConfig{}
 
 	if !m.CommentsRequired.IsNull() {
 		v := m.CommentsRequired.ValueBool()
@@ -1510,9 +1583,9 @@ func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.Lis
 		if diags.HasError() {
 			return nil
 		}
-		config.ApprovalSchemes = make([]*ApprovalSchemes, len(schemes))
+		config.ApprovalSchemes = make([]*identitynow.ApprovalSchemes, len(schemes))
 		for i, s := range schemes {
-			scheme := &ApprovalSchemes{
+			scheme := &identitynow.ApprovalSchemes{
 				ApproverType: s.ApproverType.ValueString(),
 			}
 			if !s.ApproverID.IsNull() {
@@ -1530,16 +1603,16 @@ func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.Lis
 		}
 		if len(dsModels) > 0 {
 			ds := dsModels[0]
-			dimSchema := &RoleDimensionSchema{}
+			dimSchema := &identitynow.RoleDimensionSchema{}
 			if !ds.DimensionAttributes.IsNull() && len(ds.DimensionAttributes.Elements()) > 0 {
 				var daModels []DimensionAttributeRefModel
 				diags.Append(ds.DimensionAttributes.ElementsAs(ctx, &daModels, false)...)
 				if diags.HasError() {
 					return nil
 				}
-				dimSchema.DimensionAttributes = make([]*DimensionAttributeRef, len(daModels))
+				dimSchema.DimensionAttributes = make([]*identitynow.DimensionAttributeRef, len(daModels))
 				for j, da := range daModels {
-					attrRef := &DimensionAttributeRef{
+					attrRef := &identitynow.DimensionAttributeRef{
 						Name:        da.Name.ValueString(),
 						DisplayName: da.DisplayName.ValueString(),
 					}
@@ -1556,7 +1629,7 @@ func roleAccessRequestConfigModelToAPI(ctx context.Context, configList types.Lis
 }
 
 // roleAccessRequestConfigAPIToState converts the API RoleAccessRequestConfig to a Terraform state list.
-func roleAccessRequestConfigAPIToState(ctx context.Context, config *RoleAccessRequestConfig, diags *diag.Diagnostics) types.List {
+func roleAccessRequestConfigAPIToState(ctx context.Context, config *identitynow.RoleAccessRequestConfig, diags *diag.Diagnostics) types.List {
 	arcObjType := roleAccessRequestConfigObjectType()
 
 	if config == nil {
@@ -4,13 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &ScheduleAccountAggregationResource{}
+var _ resource.ResourceWithImportState = &ScheduleAccountAggregationResource{}
+var _ resource.ResourceWithIdentity = &ScheduleAccountAggregationResource{}
 
 func NewScheduleAccountAggregationResource() resource.Resource {
 	return &ScheduleAccountAggregationResource{}
@@ -21,9 +29,10 @@ type ScheduleAccountAggregationResource struct {
 }
 
 type ScheduleAccountAggregationResourceModel struct {
-	ID              types.String `tfsdk:"id"`
-	SourceID        types.String `tfsdk:"source_id"`
-	CronExpressions types.List   `tfsdk:"cron_expressions"`
+	ID              types.String   `tfsdk:"id"`
+	SourceID        types.String   `tfsdk:"source_id"`
+	CronExpressions types.List     `tfsdk:"cron_expressions"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ScheduleAccountAggregationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -41,6 +50,10 @@ func (r *ScheduleAccountAggregationResource) Schema(ctx context.Context, req res
 			"source_id": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Source ID",
+				// The schedule belongs to its source, which is also the identity
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"cron_expressions": schema.ListAttribute{
 				Required:            true,
@@ -48,6 +61,30 @@ func (r *ScheduleAccountAggregationResource) Schema(ctx context.Context, req res
 				ElementType:         types.StringType,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ScheduleAccountAggregationIdentityModel is the identity of an aggregation schedule, addressed by its source
+type ScheduleAccountAggregationIdentityModel struct {
+	SourceID types.String `tfsdk:"source_id"`
+}
+
+func (r *ScheduleAccountAggregationResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"source_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The id of the aggregated source",
+			},
+		},
 	}
 }
 
@@ -64,26 +101,39 @@ func (r *ScheduleAccountAggregationResource) Configure(ctx context.Context, req
 }
 
 func (r *ScheduleAccountAggregationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_schedule_account_aggregation", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_schedule_account_aggregation", "create") {
+		return
+	}
+
 	var data ScheduleAccountAggregationResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var cronExpressions []string
 	resp.Diagnostics.Append(data.CronExpressions.ElementsAs(ctx, &cronExpressions, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	schedule := &AccountAggregationSchedule{
+	schedule := &identitynow.AccountAggregationSchedule{
 		SourceID:        data.SourceID.ValueString(),
 		CronExpressions: cronExpressions,
 	}
 
 	tflog.Info(ctx, "Creating Account Aggregation Schedule", map[string]interface{}{"source_id": schedule.SourceID})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -106,18 +156,28 @@ func (r *ScheduleAccountAggregationResource) Create(ctx context.Context, req res
 	data.CronExpressions = cronList
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, ScheduleAccountAggregationIdentityModel{SourceID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *ScheduleAccountAggregationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_schedule_account_aggregation", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data ScheduleAccountAggregationResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Account Aggregation Schedule", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -125,7 +185,7 @@ func (r *ScheduleAccountAggregationResource) Read(ctx context.Context, req resou
 
 	schedule, err := client.GetAccountAggregationSchedule(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -133,10 +193,10 @@ func (r *ScheduleAccountAggregationResource) Read(ctx context.Context, req resou
 		return
 	}
 
-	if schedule.CronExpressions != nil {
-		schedule.SourceID = data.ID.ValueString()
-		data.SourceID = types.StringValue(schedule.SourceID)
+	// The id is the source id, after an import it is all the state holds
+	data.SourceID = data.ID
 
+	if schedule.CronExpressions != nil {
 		cronList, diags := types.ListValueFrom(ctx, types.StringType, schedule.CronExpressions)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
@@ -146,29 +206,43 @@ func (r *ScheduleAccountAggregationResource) Read(ctx context.Context, req resou
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, ScheduleAccountAggregationIdentityModel{SourceID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *ScheduleAccountAggregationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_schedule_account_aggregation", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_schedule_account_aggregation", "update") {
+		return
+	}
+
 	var data ScheduleAccountAggregationResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var cronExpressions []string
 	resp.Diagnostics.Append(data.CronExpressions.ElementsAs(ctx, &cronExpressions, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	schedule := &AccountAggregationSchedule{
+	schedule := &identitynow.AccountAggregationSchedule{
 		SourceID:        data.SourceID.ValueString(),
 		CronExpressions: cronExpressions,
 	}
 
 	tflog.Info(ctx, "Updating Account Aggregation Schedule", map[string]interface{}{"source_id": schedule.SourceID})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -191,18 +265,32 @@ func (r *ScheduleAccountAggregationResource) Update(ctx context.Context, req res
 	data.CronExpressions = cronList
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, ScheduleAccountAggregationIdentityModel{SourceID: data.ID}, &resp.Diagnostics)
 }
 
 func (r *ScheduleAccountAggregationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_schedule_account_aggregation", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_schedule_account_aggregation", "delete") {
+		return
+	}
+
 	var data ScheduleAccountAggregationResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Account Aggregation Schedule", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -210,7 +298,7 @@ func (r *ScheduleAccountAggregationResource) Delete(ctx context.Context, req res
 
 	schedule, err := client.GetAccountAggregationSchedule(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account aggregation schedule: %s", err))
@@ -226,3 +314,10 @@ func (r *ScheduleAccountAggregationResource) Delete(ctx context.Context, req res
 		}
 	}
 }
+
+func (r *ScheduleAccountAggregationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_schedule_account_aggregation", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("source_id"), req, resp)
+}
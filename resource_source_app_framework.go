@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -12,10 +13,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &SourceAppResource{}
 var _ resource.ResourceWithImportState = &SourceAppResource{}
+var _ resource.ResourceWithIdentity = &SourceAppResource{}
 
 func NewSourceAppResource() resource.Resource {
 	return &SourceAppResource{}
@@ -26,12 +29,13 @@ type SourceAppResource struct {
 }
 
 type SourceAppResourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	Name             types.String `tfsdk:"name"`
-	Description      types.String `tfsdk:"description"`
-	Enabled          types.Bool   `tfsdk:"enabled"`
-	MatchAllAccounts types.Bool   `tfsdk:"match_all_accounts"`
-	Source           types.List   `tfsdk:"source"`
+	ID               types.String   `tfsdk:"id"`
+	Name             types.String   `tfsdk:"name"`
+	Description      types.String   `tfsdk:"description"`
+	Enabled          types.Bool     `tfsdk:"enabled"`
+	MatchAllAccounts types.Bool     `tfsdk:"match_all_accounts"`
+	Source           types.List     `tfsdk:"source"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
 }
 
 type SourceAppSourceModel struct {
@@ -73,6 +77,12 @@ func (r *SourceAppResource) Schema(ctx context.Context, req resource.SchemaReque
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"source": schema.ListNestedBlock{
 				MarkdownDescription: "Account source for the source app",
 				NestedObject: schema.NestedBlockObject{
@@ -97,6 +107,10 @@ func (r *SourceAppResource) Schema(ctx context.Context, req resource.SchemaReque
 	}
 }
 
+func (r *SourceAppResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *SourceAppResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -110,13 +124,26 @@ func (r *SourceAppResource) Configure(ctx context.Context, req resource.Configur
 }
 
 func (r *SourceAppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source_app", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source_app", "create") {
+		return
+	}
+
 	var data SourceAppResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	sa := &SourceApp{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sa := &identitynow.SourceApp{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -137,7 +164,7 @@ func (r *SourceAppResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 	if len(sources) > 0 {
-		sa.SourceAppSource = &ObjectInfo{
+		sa.SourceAppSource = &identitynow.ObjectInfo{
 			ID:   sources[0].ID.ValueString(),
 			Name: sources[0].Name.ValueString(),
 			Type: sources[0].Type.ValueString(),
@@ -146,7 +173,7 @@ func (r *SourceAppResource) Create(ctx context.Context, req resource.CreateReque
 
 	tflog.Info(ctx, "Creating Source App", map[string]interface{}{"name": sa.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -161,18 +188,28 @@ func (r *SourceAppResource) Create(ctx context.Context, req resource.CreateReque
 	data.ID = types.StringValue(newSA.ID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceAppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source_app", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data SourceAppResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Source App", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -180,7 +217,7 @@ func (r *SourceAppResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	sa, err := client.GetSourceApp(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -218,34 +255,48 @@ func (r *SourceAppResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceAppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source_app", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source_app", "update") {
+		return
+	}
+
 	var data SourceAppResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Source App", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
 	}
 
-	updatePatches := []*UpdateSourceApp{
+	updatePatches := []*identitynow.UpdateSourceApp{
 		{Op: "replace", Path: "/name", Value: data.Name.ValueString()},
 		{Op: "replace", Path: "/description", Value: data.Description.ValueString()},
 	}
 
 	if !data.Enabled.IsNull() {
-		updatePatches = append(updatePatches, &UpdateSourceApp{Op: "replace", Path: "/enabled", Value: data.Enabled.ValueBool()})
+		updatePatches = append(updatePatches, &identitynow.UpdateSourceApp{Op: "replace", Path: "/enabled", Value: data.Enabled.ValueBool()})
 	}
 
 	if !data.MatchAllAccounts.IsNull() {
-		updatePatches = append(updatePatches, &UpdateSourceApp{Op: "replace", Path: "/matchAllAccounts", Value: data.MatchAllAccounts.ValueBool()})
+		updatePatches = append(updatePatches, &identitynow.UpdateSourceApp{Op: "replace", Path: "/matchAllAccounts", Value: data.MatchAllAccounts.ValueBool()})
 	}
 
 	_, err = client.UpdateSourceApp(ctx, updatePatches, data.ID.ValueString())
@@ -255,18 +306,32 @@ func (r *SourceAppResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceAppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source_app", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source_app", "delete") {
+		return
+	}
+
 	var data SourceAppResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Source App", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.SourceApps(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -274,7 +339,7 @@ func (r *SourceAppResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	sa, err := client.GetSourceApp(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get source app: %s", err))
@@ -289,5 +354,13 @@ func (r *SourceAppResource) Delete(ctx context.Context, req resource.DeleteReque
 }
 
 func (r *SourceAppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_source_app", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, sourceAppListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -13,10 +14,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &SourceResource{}
 var _ resource.ResourceWithImportState = &SourceResource{}
+var _ resource.ResourceWithIdentity = &SourceResource{}
 
 func NewSourceResource() resource.Resource {
 	return &SourceResource{}
@@ -27,14 +30,15 @@ type SourceResource struct {
 }
 
 type SourceResourceModel struct {
-	ID              types.String `tfsdk:"id"`
-	Name            types.String `tfsdk:"name"`
-	Description     types.String `tfsdk:"description"`
-	Owner           types.List   `tfsdk:"owner"`
-	Cluster         types.List   `tfsdk:"cluster"`
-	Connector       types.String `tfsdk:"connector"`
-	DeleteThreshold types.Int64  `tfsdk:"delete_threshold"`
-	Authoritative   types.Bool   `tfsdk:"authoritative"`
+	ID              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	Description     types.String   `tfsdk:"description"`
+	Owner           types.List     `tfsdk:"owner"`
+	Cluster         types.List     `tfsdk:"cluster"`
+	Connector       types.String   `tfsdk:"connector"`
+	DeleteThreshold types.Int64    `tfsdk:"delete_threshold"`
+	Authoritative   types.Bool     `tfsdk:"authoritative"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
 type SourceOwnerModel struct {
@@ -86,6 +90,12 @@ func (r *SourceResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
@@ -108,6 +118,10 @@ func (r *SourceResource) Schema(ctx context.Context, req resource.SchemaRequest,
 	}
 }
 
+func (r *SourceResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *SourceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -121,13 +135,26 @@ func (r *SourceResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func (r *SourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source", "create") {
+		return
+	}
+
 	var data SourceResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	source := &Source{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source := &identitynow.Source{
 		Name:            data.Name.ValueString(),
 		Description:     data.Description.ValueString(),
 		Connector:       data.Connector.ValueString(),
@@ -141,7 +168,7 @@ func (r *SourceResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 	if len(owners) > 0 {
-		source.Owner = &Owner{
+		source.Owner = &identitynow.Owner{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -155,7 +182,7 @@ func (r *SourceResource) Create(ctx context.Context, req resource.CreateRequest,
 			return
 		}
 		if len(clusters) > 0 {
-			source.Cluster = &Cluster{
+			source.Cluster = &identitynow.Cluster{
 				ID:   clusters[0].ID.ValueString(),
 				Type: clusters[0].Type.ValueString(),
 				Name: clusters[0].Name.ValueString(),
@@ -165,7 +192,7 @@ func (r *SourceResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	tflog.Info(ctx, "Creating Source", map[string]interface{}{"name": source.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -179,16 +206,26 @@ func (r *SourceResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	data.ID = types.StringValue(newSource.ID)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data SourceResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -196,7 +233,7 @@ func (r *SourceResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	source, err := client.GetSource(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -247,22 +284,36 @@ func (r *SourceResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source", "update") {
+		return
+	}
+
 	var data SourceResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
 	}
 
-	source := &Source{
+	source := &identitynow.Source{
 		ID:              data.ID.ValueString(),
 		Name:            data.Name.ValueString(),
 		Description:     data.Description.ValueString(),
@@ -278,16 +329,30 @@ func (r *SourceResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *SourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_source", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_source", "delete") {
+		return
+	}
+
 	var data SourceResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.client.Sources(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", err.Error())
 		return
@@ -295,7 +360,7 @@ func (r *SourceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	source, err := client.GetSource(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", err.Error())
@@ -310,5 +375,13 @@ func (r *SourceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *SourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_source", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, sourceListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
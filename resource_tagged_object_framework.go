@@ -6,20 +6,24 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &TaggedObjectResource{}
 var _ resource.ResourceWithImportState = &TaggedObjectResource{}
+var _ resource.ResourceWithIdentity = &TaggedObjectResource{}
 
 func NewTaggedObjectResource() resource.Resource {
 	return &TaggedObjectResource{}
@@ -34,10 +38,13 @@ type TaggedObjectResourceModel struct {
 	ObjectType types.String                  `tfsdk:"object_type"`
 	ObjectIDs  types.Set                     `tfsdk:"object_ids"`
 	Tags       CaseInsensitiveStringSetValue `tfsdk:"tags"`
+	Timeouts   timeouts.Value                `tfsdk:"timeouts"`
 }
 
 func (r *TaggedObjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_tagged_object"
+	// object_ids can change in place, and with them the identity
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 func (r *TaggedObjectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -72,9 +79,49 @@ func (r *TaggedObjectResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
+// TaggedObjectIdentityModel is the identity of the tagged objects, with object_ids sorted
+type TaggedObjectIdentityModel struct {
+	ObjectType types.String `tfsdk:"object_type"`
+	ObjectIDs  types.List   `tfsdk:"object_ids"`
+}
+
+func (r *TaggedObjectResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"object_type": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Type of the tagged objects",
+			},
+			"object_ids": identityschema.ListAttribute{
+				RequiredForImport: true,
+				ElementType:       types.StringType,
+				Description:       "IDs of the tagged objects",
+			},
+		},
+	}
+}
+
+// taggedObjectIdentity builds the identity of the objects in data
+func taggedObjectIdentity(ctx context.Context, data TaggedObjectResourceModel, diags *diag.Diagnostics) TaggedObjectIdentityModel {
+	var objectIDs []string
+	diags.Append(data.ObjectIDs.ElementsAs(ctx, &objectIDs, false)...)
+	sort.Strings(objectIDs)
+	idList, d := types.ListValueFrom(ctx, types.StringType, objectIDs)
+	diags.Append(d...)
+	return TaggedObjectIdentityModel{ObjectType: data.ObjectType, ObjectIDs: idList}
+}
+
 func (r *TaggedObjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -88,12 +135,25 @@ func (r *TaggedObjectResource) Configure(ctx context.Context, req resource.Confi
 }
 
 func (r *TaggedObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_tagged_object", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_tagged_object", "create") {
+		return
+	}
+
 	var data TaggedObjectResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var tags []string
 	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
 	if resp.Diagnostics.HasError() {
@@ -110,7 +170,7 @@ func (r *TaggedObjectResource) Create(ctx context.Context, req resource.CreateRe
 
 	objectType := data.ObjectType.ValueString()
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.TaggedObjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -123,8 +183,8 @@ func (r *TaggedObjectResource) Create(ctx context.Context, req resource.CreateRe
 			"tags":        upperTags,
 		})
 
-		taggedObject := &TaggedObject{
-			ObjectRef: &TaggedObjectRef{
+		taggedObject := &identitynow.TaggedObject{
+			ObjectRef: &identitynow.TaggedObjectRef{
 				Type: objectType,
 				ID:   objectID,
 			},
@@ -145,15 +205,25 @@ func (r *TaggedObjectResource) Create(ctx context.Context, req resource.CreateRe
 	data.ID = types.StringValue(computeID(objectType, objectIDs))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, taggedObjectIdentity(ctx, data, &resp.Diagnostics), &resp.Diagnostics)
 }
 
 func (r *TaggedObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_tagged_object", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data TaggedObjectResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var objectIDs []string
 	resp.Diagnostics.Append(data.ObjectIDs.ElementsAs(ctx, &objectIDs, false)...)
 	if resp.Diagnostics.HasError() {
@@ -162,7 +232,7 @@ func (r *TaggedObjectResource) Read(ctx context.Context, req resource.ReadReques
 
 	objectType := data.ObjectType.ValueString()
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.TaggedObjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -178,7 +248,7 @@ func (r *TaggedObjectResource) Read(ctx context.Context, req resource.ReadReques
 
 		taggedObject, err := client.GetTaggedObject(ctx, objectType, objectID)
 		if err != nil {
-			if _, notFound := err.(*NotFoundError); notFound {
+			if identitynow.IsNotFound(err) {
 				resp.State.RemoveResource(ctx)
 				return
 			}
@@ -202,15 +272,29 @@ func (r *TaggedObjectResource) Read(ctx context.Context, req resource.ReadReques
 	data.ID = types.StringValue(computeID(objectType, objectIDs))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, taggedObjectIdentity(ctx, data, &resp.Diagnostics), &resp.Diagnostics)
 }
 
 func (r *TaggedObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_tagged_object", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_tagged_object", "update") {
+		return
+	}
+
 	var data TaggedObjectResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var tags []string
 	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
 	if resp.Diagnostics.HasError() {
@@ -239,7 +323,7 @@ func (r *TaggedObjectResource) Update(ctx context.Context, req resource.UpdateRe
 
 	objectType := data.ObjectType.ValueString()
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.TaggedObjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -253,8 +337,8 @@ func (r *TaggedObjectResource) Update(ctx context.Context, req resource.UpdateRe
 			"tags":        upperTags,
 		})
 
-		taggedObject := &TaggedObject{
-			ObjectRef: &TaggedObjectRef{
+		taggedObject := &identitynow.TaggedObject{
+			ObjectRef: &identitynow.TaggedObjectRef{
 				Type: objectType,
 				ID:   objectID,
 			},
@@ -278,7 +362,7 @@ func (r *TaggedObjectResource) Update(ctx context.Context, req resource.UpdateRe
 			})
 			err = client.DeleteTaggedObject(ctx, objectType, priorID)
 			if err != nil {
-				if _, notFound := err.(*NotFoundError); !notFound {
+				if !identitynow.IsNotFound(err) {
 					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete tagged object %s/%s: %s", objectType, priorID, err))
 					return
 				}
@@ -293,15 +377,29 @@ func (r *TaggedObjectResource) Update(ctx context.Context, req resource.UpdateRe
 	data.ID = types.StringValue(computeID(objectType, plannedIDs))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIdentity(ctx, resp.Identity, taggedObjectIdentity(ctx, data, &resp.Diagnostics), &resp.Diagnostics)
 }
 
 func (r *TaggedObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_tagged_object", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_tagged_object", "delete") {
+		return
+	}
+
 	var data TaggedObjectResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var objectIDs []string
 	resp.Diagnostics.Append(data.ObjectIDs.ElementsAs(ctx, &objectIDs, false)...)
 	if resp.Diagnostics.HasError() {
@@ -310,7 +408,7 @@ func (r *TaggedObjectResource) Delete(ctx context.Context, req resource.DeleteRe
 
 	objectType := data.ObjectType.ValueString()
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.TaggedObjects(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -324,7 +422,7 @@ func (r *TaggedObjectResource) Delete(ctx context.Context, req resource.DeleteRe
 
 		_, err = client.GetTaggedObject(ctx, objectType, objectID)
 		if err != nil {
-			if _, notFound := err.(*NotFoundError); notFound {
+			if identitynow.IsNotFound(err) {
 				continue
 			}
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get tagged object %s/%s: %s", objectType, objectID, err))
@@ -340,18 +438,32 @@ func (r *TaggedObjectResource) Delete(ctx context.Context, req resource.DeleteRe
 }
 
 func (r *TaggedObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import ID format: {object_type}/{object_id1},{object_id2},...
-	parts := strings.SplitN(req.ID, "/", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import ID format: {object_type}/{object_id1},{object_id2},..., got: %s", req.ID),
-		)
-		return
-	}
+	ctx, span := startOperationSpan(ctx, "identitynow_tagged_object", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
 
-	objectType := parts[0]
-	objectIDs := strings.Split(parts[1], ",")
+	var objectType string
+	var objectIDs []string
+	if req.ID == "" {
+		var identity TaggedObjectIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		resp.Diagnostics.Append(identity.ObjectIDs.ElementsAs(ctx, &objectIDs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		objectType = identity.ObjectType.ValueString()
+	} else {
+		// Import ID format: {object_type}/{object_id1},{object_id2},...
+		parts := strings.SplitN(req.ID, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import ID format: {object_type}/{object_id1},{object_id2},..., got: %s", req.ID),
+			)
+			return
+		}
+		objectType = parts[0]
+		objectIDs = strings.Split(parts[1], ",")
+	}
 
 	idElems := make([]attr.Value, len(objectIDs))
 	for i, id := range objectIDs {
@@ -361,6 +473,7 @@ func (r *TaggedObjectResource) ImportState(ctx context.Context, req resource.Imp
 			return
 		}
 		idElems[i] = types.StringValue(trimmed)
+		objectIDs[i] = trimmed
 	}
 
 	idsSet, diags := types.SetValue(types.StringType, idElems)
@@ -371,7 +484,7 @@ func (r *TaggedObjectResource) ImportState(ctx context.Context, req resource.Imp
 
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("object_type"), objectType)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("object_ids"), idsSet)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), computeID(objectType, objectIDs))...)
 }
 
 func stringSliceToCaseInsensitiveSet(ctx context.Context, tags []string, diags *diag.Diagnostics) CaseInsensitiveStringSetValue {
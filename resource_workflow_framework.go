@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -15,10 +16,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/plsph/terraform-provider-identitynow/identitynow"
 )
 
 var _ resource.Resource = &WorkflowResource{}
 var _ resource.ResourceWithImportState = &WorkflowResource{}
+var _ resource.ResourceWithIdentity = &WorkflowResource{}
 
 func NewWorkflowResource() resource.Resource {
 	return &WorkflowResource{}
@@ -29,13 +32,14 @@ type WorkflowResource struct {
 }
 
 type WorkflowResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Owner       types.List   `tfsdk:"owner"`
-	Enabled     types.Bool   `tfsdk:"enabled"`
-	Trigger     types.List   `tfsdk:"trigger"`
-	Definition  types.List   `tfsdk:"definition"`
+	ID          types.String   `tfsdk:"id"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Owner       types.List     `tfsdk:"owner"`
+	Enabled     types.Bool     `tfsdk:"enabled"`
+	Trigger     types.List     `tfsdk:"trigger"`
+	Definition  types.List     `tfsdk:"definition"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 type WorkflowOwnerModel struct {
@@ -88,6 +92,12 @@ func (r *WorkflowResource) Schema(ctx context.Context, req resource.SchemaReques
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"owner": schema.ListNestedBlock{
 				MarkdownDescription: "Workflow owner",
 				NestedObject: schema.NestedBlockObject{
@@ -145,6 +155,10 @@ func (r *WorkflowResource) Schema(ctx context.Context, req resource.SchemaReques
 	}
 }
 
+func (r *WorkflowResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = idIdentitySchema()
+}
+
 func (r *WorkflowResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -158,13 +172,26 @@ func (r *WorkflowResource) Configure(ctx context.Context, req resource.Configure
 }
 
 func (r *WorkflowResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_workflow", "create")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_workflow", "create") {
+		return
+	}
+
 	var data WorkflowResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	workflow := &Workflow{
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Create, defaultCreateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workflow := &identitynow.Workflow{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -176,7 +203,7 @@ func (r *WorkflowResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 	if len(owners) > 0 {
-		workflow.Owner = &WorkflowOwner{
+		workflow.Owner = &identitynow.WorkflowOwner{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -220,7 +247,7 @@ func (r *WorkflowResource) Create(ctx context.Context, req resource.CreateReques
 
 	tflog.Info(ctx, "Creating Workflow", map[string]interface{}{"name": workflow.Name})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workflows(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -239,18 +266,28 @@ func (r *WorkflowResource) Create(ctx context.Context, req resource.CreateReques
 
 	tflog.Trace(ctx, "created a workflow resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *WorkflowResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_workflow", "read")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
 	var data WorkflowResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Read, defaultReadTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Reading Workflow", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workflows(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -258,7 +295,7 @@ func (r *WorkflowResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	workflow, err := client.GetWorkflow(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -355,18 +392,32 @@ func (r *WorkflowResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *WorkflowResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_workflow", "update")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_workflow", "update") {
+		return
+	}
+
 	var data WorkflowResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Update, defaultUpdateTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Updating Workflow", map[string]interface{}{"id": data.ID.ValueString()})
 
-	workflow := &Workflow{
+	workflow := &identitynow.Workflow{
 		Name:        data.Name.ValueString(),
 		Description: data.Description.ValueString(),
 	}
@@ -378,7 +429,7 @@ func (r *WorkflowResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 	if len(owners) > 0 {
-		workflow.Owner = &WorkflowOwner{
+		workflow.Owner = &identitynow.WorkflowOwner{
 			ID:   owners[0].ID.ValueString(),
 			Type: owners[0].Type.ValueString(),
 			Name: owners[0].Name.ValueString(),
@@ -420,7 +471,7 @@ func (r *WorkflowResource) Update(ctx context.Context, req resource.UpdateReques
 		}
 	}
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workflows(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -437,18 +488,32 @@ func (r *WorkflowResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	setIDIdentity(ctx, resp.Identity, data.ID, &resp.Diagnostics)
 }
 
 func (r *WorkflowResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startOperationSpan(ctx, "identitynow_workflow", "delete")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	if !r.client.checkWritable(&resp.Diagnostics, "identitynow_workflow", "delete") {
+		return
+	}
+
 	var data WorkflowResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.Delete, defaultDeleteTimeout, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Info(ctx, "Deleting Workflow", map[string]interface{}{"id": data.ID.ValueString()})
 
-	client, err := r.client.IdentityNowClient(ctx)
+	client, err := r.client.Workflows(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get IdentityNow client: %s", err))
 		return
@@ -456,7 +521,7 @@ func (r *WorkflowResource) Delete(ctx context.Context, req resource.DeleteReques
 
 	err = client.DeleteWorkflow(ctx, data.ID.ValueString())
 	if err != nil {
-		if _, notFound := err.(*NotFoundError); notFound {
+		if identitynow.IsNotFound(err) {
 			return
 		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete workflow: %s", err))
@@ -465,12 +530,20 @@ func (r *WorkflowResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *WorkflowResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	ctx, span := startOperationSpan(ctx, "identitynow_workflow", "import")
+	defer endOperationSpan(span, &resp.Diagnostics)
+
+	req.ID = r.client.importIDByName(ctx, req.ID, workflowListing, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
 }
 
 // workflowTriggerModelToAPI converts a WorkflowTriggerModel to the API WorkflowTrigger struct.
-func workflowTriggerModelToAPI(m WorkflowTriggerModel, diags *diag.Diagnostics) *WorkflowTrigger {
-	trigger := &WorkflowTrigger{
+func workflowTriggerModelToAPI(m WorkflowTriggerModel, diags *diag.Diagnostics) *identitynow.WorkflowTrigger {
+	trigger := &identitynow.WorkflowTrigger{
 		Type: m.Type.ValueString(),
 	}
 	if !m.DisplayName.IsNull() {
@@ -488,8 +561,8 @@ func workflowTriggerModelToAPI(m WorkflowTriggerModel, diags *diag.Diagnostics)
 }
 
 // workflowDefinitionModelToAPI converts a WorkflowDefinitionModel to the API WorkflowDefinition struct.
-func workflowDefinitionModelToAPI(m WorkflowDefinitionModel, diags *diag.Diagnostics) *WorkflowDefinition {
-	def := &WorkflowDefinition{
+func workflowDefinitionModelToAPI(m WorkflowDefinitionModel, diags *diag.Diagnostics) *identitynow.WorkflowDefinition {
+	def := &identitynow.WorkflowDefinition{
 		Start: m.Start.ValueString(),
 	}
 	if !m.StepsJSON.IsNull() && m.StepsJSON.ValueString() != "" {
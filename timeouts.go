@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Operation deadlines used when a resource has no timeouts block
+const (
+	defaultCreateTimeout = 30 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 30 * time.Minute
+	defaultDeleteTimeout = 30 * time.Minute
+)
+
+// timeoutFunc is one of the Create, Read, Update or Delete methods of timeouts.Value
+type timeoutFunc func(ctx context.Context, defaultTimeout time.Duration) (time.Duration, diag.Diagnostics)
+
+// withTimeout derives the deadline of a resource operation from its timeouts block, so retries,
+// rate limiting and task polling all stop once the configured time is up
+func withTimeout(ctx context.Context, timeout timeoutFunc, defaultTimeout time.Duration, diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	duration, timeoutDiags := timeout(ctx, defaultTimeout)
+	diags.Append(timeoutDiags...)
+	if timeoutDiags.HasError() {
+		duration = defaultTimeout
+	}
+	return context.WithTimeout(ctx, duration)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/plsph/terraform-provider-identitynow"
+
+	// traceFileEnv names a file that receives spans as JSON, for local debugging without a collector
+	traceFileEnv = "IDENTITYNOW_TRACE_FILE"
+)
+
+// setupTracing installs a tracer provider when the standard OTEL_* variables ask for an OTLP exporter
+// or IDENTITYNOW_TRACE_FILE is set. Without them spans are no-ops.
+// The returned function flushes pending spans and must be called before the process exits.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return noop, nil
+	}
+
+	var exporters []sdktrace.SpanExporter
+	if otlpTracingEnabled() {
+		// Endpoint, headers, TLS and timeout are read from OTEL_EXPORTER_OTLP_* by the exporter
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	if path := os.Getenv(traceFileEnv); path != "" {
+		// Never write spans to stdout, it carries the plugin protocol handshake
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", traceFileEnv, err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("creating file trace exporter: %w", err)
+		}
+		exporters = append(exporters, fileSpanExporter{SpanExporter: exporter, file: f})
+	}
+	if len(exporters) == 0 {
+		return noop, nil
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "terraform-provider-identitynow"),
+			attribute.String("service.version", version),
+		),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	for _, exporter := range exporters {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// otlpTracingEnabled follows the OTEL_TRACES_EXPORTER convention, an endpoint alone also enables the exporter
+func otlpTracingEnabled() bool {
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "otlp":
+		return true
+	case "none":
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// fileSpanExporter closes the trace file once the exporter is shut down
+type fileSpanExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e fileSpanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName, trace.WithInstrumentationVersion(version))
+}
+
+// startOperationSpan starts the span of a resource operation, every API call it makes becomes a child span
+func startOperationSpan(ctx context.Context, resourceType string, operation string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, resourceType+"."+operation, trace.WithAttributes(
+		attribute.String("terraform.resource_type", resourceType),
+		attribute.String("terraform.operation", operation),
+	))
+}
+
+// endOperationSpan ends an operation span, marking it failed when the response has error diagnostics
+func endOperationSpan(span trace.Span, diags *diag.Diagnostics) {
+	if diags.HasError() {
+		for _, d := range diags.Errors() {
+			span.RecordError(errors.New(d.Summary() + ": " + d.Detail()))
+		}
+		span.SetStatus(codes.Error, diags.Errors()[0].Summary())
+	}
+	span.End()
+}